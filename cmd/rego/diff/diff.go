@@ -0,0 +1,110 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.aporeto.io/regolithe/spec"
+)
+
+// ErrBreakingChanges is returned by Write when
+// breaking changes have been detected.
+var ErrBreakingChanges = errors.New("breaking changes detected")
+
+// Load loads the specification set from the given source. If the source
+// is an existing directory, it is loaded directly. Otherwise it is
+// considered as a git revision of the repository containing repoPath,
+// and the specifications are read from internalPath in that revision.
+// Only the specifications matching the given selector expression are
+// kept.
+func Load(source string, repoPath string, internalPath string, selector string) (spec.SpecificationSet, error) {
+
+	opts := []spec.LoadOption{
+		spec.OptionLogger(slog.Default()),
+		spec.OptionSelect(selector),
+	}
+
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return spec.LoadSpecificationSet(source, nil, nil, "", opts...)
+	}
+
+	tmpFolder, err := os.MkdirTemp("", "regolithe-diff")
+	if err != nil {
+		return nil, err
+	}
+	defer func(f string) { _ = os.RemoveAll(f) }(tmpFolder) // nolint: errcheck
+
+	if err := spec.ExtractRevision(repoPath, source, internalPath, tmpFolder); err != nil {
+		return nil, fmt.Errorf("unable to extract revision '%s': %w", source, err)
+	}
+
+	return spec.LoadSpecificationSet(tmpFolder, nil, nil, "", opts...)
+}
+
+// Write writes the given changes in the given format. It returns
+// ErrBreakingChanges if at least one of the changes is breaking.
+func Write(w io.Writer, changes spec.Changes, format string) error {
+
+	switch format {
+
+	case "text":
+		if len(changes) == 0 {
+			_, _ = fmt.Fprintln(w, "No changes.")
+		}
+
+		for _, level := range []spec.ChangeLevel{
+			spec.ChangeLevelBreaking,
+			spec.ChangeLevelNonBreaking,
+			spec.ChangeLevelInformational,
+		} {
+
+			cs := changes.Level(level)
+			if len(cs) == 0 {
+				continue
+			}
+
+			_, _ = fmt.Fprintf(w, "%s changes (%d):\n\n", strings.ToUpper(string(level)[:1])+string(level)[1:], len(cs))
+			for _, c := range cs {
+				_, _ = fmt.Fprintf(w, "  - %s: %s\n", c.Path, c.Message)
+			}
+			_, _ = fmt.Fprintln(w)
+		}
+
+	case "json":
+		if changes == nil {
+			changes = spec.Changes{}
+		}
+
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal changes: %w", err)
+		}
+
+		_, _ = fmt.Fprintln(w, string(data))
+
+	default:
+		return fmt.Errorf("unsupported diff format: %s", format)
+	}
+
+	if changes.Breaking() {
+		return ErrBreakingChanges
+	}
+
+	return nil
+}
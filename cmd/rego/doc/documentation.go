@@ -42,6 +42,8 @@ var functions = template.FuncMap{
 	"example":          makeExample,
 	"typeOf":           typeOf,
 	"makeDefaultValue": makeDefaultValue,
+	"makeEnumValues":   makeEnumValues,
+	"makeProperties":   makeProperties,
 }
 
 // Write writes the documentation for the given spec.SpecificationSet.
@@ -117,8 +119,7 @@ func writeMarkdownDoc(set spec.SpecificationSet) error {
 				continue
 			}
 
-			_, ok := model.Extensions["forceDocumentation"]
-			if model.Private && !ok {
+			if !spec.IsPublic(model) {
 				continue
 			}
 
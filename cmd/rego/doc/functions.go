@@ -30,7 +30,12 @@ func typeOf(attr *spec.Attribute) string {
 	case spec.AttributeTypeExt:
 		return "`" + attr.SubType + "`"
 	case spec.AttributeTypeList:
+		if attr.Items != nil && len(attr.Items.AllowedChoices) > 0 {
+			return "`[]enum(" + strings.Join(attr.Items.AllowedChoices, " | ") + ")`"
+		}
 		return "`[]" + attr.SubType + "`"
+	case spec.AttributeTypeMap:
+		return "`map[string]" + attr.SubType + "`"
 	case spec.AttributeTypeEnum:
 		return "`enum(" + strings.Join(attr.AllowedChoices, " | ") + ")`"
 	case spec.AttributeTypeRef:
@@ -53,16 +58,15 @@ func toc(specs []spec.Specification) string {
 	fmt.Fprintln(w, "| Resource \t|\t Description \t|") // nolint: errcheck
 	fmt.Fprintln(w, "| - \t|\t - \t|")                  // nolint: errcheck
 
-	for _, spec := range specs {
+	for _, s := range specs {
 
-		model := spec.Model()
+		model := s.Model()
 
 		if model.Group == "none" {
 			continue
 		}
 
-		_, ok := model.Extensions["forceDocumentation"]
-		if model.Private && !ok {
+		if !spec.IsPublic(model) {
 			continue
 		}
 
@@ -237,10 +241,11 @@ func operations(spec spec.Specification, relationships map[string]*spec.Relation
 			for _, pd := range r.params.Entries {
 
 				var enumValues string
-				if pd.Type == "enum" {
+				if pd.Type == "enum" && pd.Choices() == nil {
 					enumValues = "(" + strings.Join(pd.AllowedChoices, " | ") + ")"
 				}
 				_, _ = buf.WriteString(fmt.Sprintf("- `%s` (`%s%s`): %s\n", pd.Name, pd.Type, enumValues, strings.Replace(pd.Description, "\n", "", -1)))
+				_, _ = buf.WriteString(makeEnumValueList(pd.Choices(), "  "))
 			}
 
 			if r.params.Required != nil {
@@ -311,20 +316,63 @@ func characteristics(attr *spec.Attribute) string {
 		out = append(out, fmt.Sprintf("`format=%s`", attr.AllowedChars))
 	}
 
-	if attr.MinLength > 0 {
-		out = append(out, fmt.Sprintf("`min_length=%d`", attr.MinLength))
+	if attr.MinLength != nil {
+		out = append(out, fmt.Sprintf("`min_length=%d`", *attr.MinLength))
+	}
+
+	if attr.MaxLength != nil {
+		out = append(out, fmt.Sprintf("`max_length=%d`", *attr.MaxLength))
+	}
+
+	if attr.MinValue != nil {
+		if attr.ExclusiveMinValue {
+			out = append(out, fmt.Sprintf("`exclusive_min_value=%f`", *attr.MinValue))
+		} else {
+			out = append(out, fmt.Sprintf("`min_value=%f`", *attr.MinValue))
+		}
+	}
+
+	if attr.MaxValue != nil {
+		if attr.ExclusiveMaxValue {
+			out = append(out, fmt.Sprintf("`exclusive_max_value=%f`", *attr.MaxValue))
+		} else {
+			out = append(out, fmt.Sprintf("`max_value=%f`", *attr.MaxValue))
+		}
+	}
+
+	if attr.MultipleOf != nil {
+		out = append(out, fmt.Sprintf("`multiple_of=%f`", *attr.MultipleOf))
 	}
 
-	if attr.MaxLength > 0 {
-		out = append(out, fmt.Sprintf("`max_length=%d`", attr.MaxLength))
+	if attr.MinItems != nil {
+		out = append(out, fmt.Sprintf("`min_items=%d`", *attr.MinItems))
 	}
 
-	if attr.MinValue > 0 {
-		out = append(out, fmt.Sprintf("`min_value=%f`", attr.MinValue))
+	if attr.MaxItems != nil {
+		out = append(out, fmt.Sprintf("`max_items=%d`", *attr.MaxItems))
+	}
+
+	if attr.UniqueItems {
+		out = append(out, "`unique_items`")
+	}
+
+	if attr.Items != nil {
+
+		if attr.Items.AllowedChars != "" {
+			out = append(out, fmt.Sprintf("`item_format=%s`", attr.Items.AllowedChars))
+		}
+
+		if attr.Items.MinLength != nil {
+			out = append(out, fmt.Sprintf("`item_min_length=%d`", *attr.Items.MinLength))
+		}
+
+		if attr.Items.MaxLength != nil {
+			out = append(out, fmt.Sprintf("`item_max_length=%d`", *attr.Items.MaxLength))
+		}
 	}
 
-	if attr.MaxValue > 0 {
-		out = append(out, fmt.Sprintf("`max_value=%f`", attr.MaxValue))
+	if attr.KeyAllowedChars != "" {
+		out = append(out, fmt.Sprintf("`key_format=%s`", attr.KeyAllowedChars))
 	}
 
 	if len(out) == 0 {
@@ -334,6 +382,75 @@ func characteristics(attr *spec.Attribute) string {
 	return " [" + strings.Join(out, ",") + "]"
 }
 
+func makeEnumValues(attr *spec.Attribute) string {
+
+	values := attr.Choices()
+	if len(values) == 0 {
+		return ""
+	}
+
+	return "\n\nAllowed values:\n\n" + makeEnumValueList(values, "")
+}
+
+// makeEnumValueList renders the given enum values as a markdown
+// list, marking the deprecated ones, with each item indented by
+// the given prefix.
+func makeEnumValueList(values []*spec.EnumValue, indent string) string {
+
+	buf := &bytes.Buffer{}
+
+	for _, v := range values {
+
+		_, _ = buf.WriteString(fmt.Sprintf("%s- `%s`", indent, v.Value))
+
+		if v.Deprecated {
+			_, _ = buf.WriteString(" (deprecated)")
+		}
+
+		if v.Description != "" {
+			_, _ = buf.WriteString(": " + strings.Replace(v.Description, "\n", " ", -1))
+		}
+
+		_, _ = buf.WriteString("\n")
+	}
+
+	return buf.String()
+}
+
+func makeProperties(attr *spec.Attribute) string {
+
+	if len(attr.Properties) == 0 {
+		return ""
+	}
+
+	buf := &bytes.Buffer{}
+	_, _ = buf.WriteString("\n\nProperties:\n\n")
+
+	writeProperties(buf, attr.Properties, "")
+
+	return buf.String()
+}
+
+func writeProperties(buf *bytes.Buffer, props []*spec.Attribute, indent string) {
+
+	for _, p := range props {
+
+		_, _ = buf.WriteString(fmt.Sprintf("%s- `%s` (%s)%s", indent, p.Name, typeOf(p), characteristics(p)))
+
+		if p.Deprecated {
+			_, _ = buf.WriteString(" (deprecated)")
+		}
+
+		if p.Description != "" {
+			_, _ = buf.WriteString(": " + strings.Replace(strings.TrimSpace(p.Description), "\n", " ", -1))
+		}
+
+		_, _ = buf.WriteString("\n")
+
+		writeProperties(buf, p.Properties, indent+"  ")
+	}
+}
+
 func makeDefaultValue(attr *spec.Attribute) string {
 
 	if attr.DefaultValue == nil {
@@ -348,6 +465,28 @@ func makeDefaultValue(attr *spec.Attribute) string {
 	return fmt.Sprintf("\n\nDefault value:\n\n```json\n%s\n```\n", string(dv))
 }
 
+// exampleChoice returns the first allowed value of the enum
+// attribute that is not deprecated, or an empty string if
+// there is none.
+func exampleChoice(attr *spec.Attribute) string {
+
+	values := attr.Choices()
+	if values == nil {
+		if len(attr.AllowedChoices) == 0 {
+			return ""
+		}
+		return attr.AllowedChoices[0]
+	}
+
+	for _, v := range values {
+		if !v.Deprecated {
+			return v.Value
+		}
+	}
+
+	return ""
+}
+
 func makeExample(s spec.Specification, version string) string {
 
 	data := map[string]any{}
@@ -373,7 +512,9 @@ func makeExample(s spec.Specification, version string) string {
 		}
 
 		if attr.Type == spec.AttributeTypeEnum {
-			data[attr.Name] = attr.AllowedChoices[0]
+			if v := exampleChoice(attr); v != "" {
+				data[attr.Name] = v
+			}
 		}
 
 		if attr.Type == spec.AttributeTypeBool {
@@ -0,0 +1,406 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go.aporeto.io/regolithe/spec"
+)
+
+// Filter holds the options used to restrict
+// the specifications drawn in the graph.
+type Filter struct {
+
+	// Groups restricts the graph to the given groups.
+	// If empty, all groups are drawn.
+	Groups []string
+
+	// From is the rest name of the specification
+	// to start walking the graph from. If empty,
+	// all specifications are drawn.
+	From string
+
+	// Depth is the maximum distance from the From
+	// specification. If 0, there is no limit.
+	Depth int
+
+	// HidePrivate removes the private specifications.
+	HidePrivate bool
+}
+
+// Write writes the graph of the given spec.SpecificationSet
+// in the given format into the given writer.
+func Write(w io.Writer, set spec.SpecificationSet, format string, filter Filter) error {
+
+	g, err := newGraph(set, filter)
+	if err != nil {
+		return err
+	}
+
+	var out string
+
+	switch format {
+	case "mermaid":
+		out = g.mermaid()
+	case "dot":
+		out = g.dot()
+	default:
+		return fmt.Errorf("unsupported graph format: %s", format)
+	}
+
+	_, err = io.WriteString(w, out)
+	return err
+}
+
+// An edge represents a link between two specifications.
+type edge struct {
+	from  spec.Specification
+	to    spec.Specification
+	label string
+	ref   bool
+	many  bool
+}
+
+type graph struct {
+	nodes []spec.Specification
+	edges []edge
+}
+
+func newGraph(set spec.SpecificationSet, filter Filter) (*graph, error) {
+
+	selected := map[string]struct{}{}
+
+	for _, s := range set.Specifications() {
+
+		model := s.Model()
+
+		if filter.HidePrivate && model.Private {
+			continue
+		}
+
+		if len(filter.Groups) > 0 && !contains(filter.Groups, model.Group) {
+			continue
+		}
+
+		selected[model.RestName] = struct{}{}
+	}
+
+	if filter.From != "" {
+
+		if _, ok := selected[filter.From]; !ok {
+			return nil, fmt.Errorf("unable to find specification '%s' in the selected specifications", filter.From)
+		}
+
+		reachable := map[string]struct{}{filter.From: {}}
+		current := []string{filter.From}
+
+		for depth := 0; len(current) > 0 && (filter.Depth == 0 || depth < filter.Depth); depth++ {
+
+			var next []string
+
+			for _, name := range current {
+				for _, e := range edgesFrom(set, set.Specification(name)) {
+
+					remote := e.to.Model().RestName
+					if _, ok := selected[remote]; !ok {
+						continue
+					}
+
+					if _, ok := reachable[remote]; ok {
+						continue
+					}
+
+					reachable[remote] = struct{}{}
+					next = append(next, remote)
+				}
+			}
+
+			current = next
+		}
+
+		selected = reachable
+	}
+
+	g := &graph{}
+
+	for _, s := range set.Specifications() {
+
+		if _, ok := selected[s.Model().RestName]; !ok {
+			continue
+		}
+
+		g.nodes = append(g.nodes, s)
+
+		for _, e := range edgesFrom(set, s) {
+			if _, ok := selected[e.to.Model().RestName]; ok {
+				g.edges = append(g.edges, e)
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// edgesFrom returns the relations and the references
+// going out of the given specification.
+func edgesFrom(set spec.SpecificationSet, s spec.Specification) []edge {
+
+	var edges []edge
+
+	for _, rel := range s.Relations() {
+
+		remote := set.Specification(rel.RestName)
+		if remote == nil {
+			continue
+		}
+
+		edges = append(edges, edge{
+			from:  s,
+			to:    remote,
+			label: strings.Join(actions(rel), ", "),
+		})
+	}
+
+	for _, attr := range s.Attributes(s.LatestAttributesVersion()) {
+
+		if attr.Type != spec.AttributeTypeRef &&
+			attr.Type != spec.AttributeTypeRefList &&
+			attr.Type != spec.AttributeTypeRefMap {
+			continue
+		}
+
+		remote := set.Specification(attr.SubType)
+		if remote == nil {
+			continue
+		}
+
+		edges = append(edges, edge{
+			from:  s,
+			to:    remote,
+			label: attr.Name,
+			ref:   true,
+			many:  attr.Type != spec.AttributeTypeRef,
+		})
+	}
+
+	return edges
+}
+
+func (g *graph) mermaid() string {
+
+	buf := &strings.Builder{}
+
+	_, _ = buf.WriteString("erDiagram\n")
+
+	for _, s := range g.nodes {
+
+		attrs := keyAttributes(s)
+
+		if len(attrs) == 0 {
+			_, _ = fmt.Fprintf(buf, "    %s\n", s.Model().EntityName)
+			continue
+		}
+
+		_, _ = fmt.Fprintf(buf, "    %s {\n", s.Model().EntityName)
+		for _, attr := range attrs {
+
+			var keys []string
+			if attr.Identifier || attr.PrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if attr.ForeignKey {
+				keys = append(keys, "FK")
+			}
+
+			line := fmt.Sprintf("        %s %s", mermaidType(attr), attr.Name)
+			if len(keys) > 0 {
+				line += " " + strings.Join(keys, ",")
+			}
+			if attr.Required {
+				line += ` "required"`
+			}
+
+			_, _ = fmt.Fprintln(buf, line)
+		}
+		_, _ = buf.WriteString("    }\n")
+	}
+
+	for _, e := range g.edges {
+
+		if e.ref {
+			cardinality := "o|"
+			if e.many {
+				cardinality = "o{"
+			}
+			_, _ = fmt.Fprintf(buf, "    %s }o..%s %s : \"%s\"\n",
+				e.from.Model().EntityName,
+				cardinality,
+				e.to.Model().EntityName,
+				e.label,
+			)
+			continue
+		}
+
+		_, _ = fmt.Fprintf(buf, "    %s ||--o{ %s : \"%s\"\n",
+			e.from.Model().EntityName,
+			e.to.Model().EntityName,
+			e.label,
+		)
+	}
+
+	return buf.String()
+}
+
+func (g *graph) dot() string {
+
+	buf := &strings.Builder{}
+
+	_, _ = buf.WriteString("digraph specifications {\n")
+	_, _ = buf.WriteString("    node [shape=record];\n")
+
+	for _, s := range g.nodes {
+
+		fields := []string{s.Model().EntityName}
+		for _, attr := range keyAttributes(s) {
+			field := fmt.Sprintf("%s: %s", attr.Name, typeName(attr))
+			if attr.Identifier || attr.PrimaryKey {
+				field += " (PK)"
+			}
+			if attr.ForeignKey {
+				field += " (FK)"
+			}
+			if attr.Required {
+				field += " (required)"
+			}
+			fields = append(fields, escapeRecord(field))
+		}
+
+		style := ""
+		if s.Model().Private {
+			style = ", style=dashed"
+		}
+
+		_, _ = fmt.Fprintf(buf, "    %q [label=\"{%s}\"%s];\n", s.Model().RestName, strings.Join(fields, "|"), style)
+	}
+
+	for _, e := range g.edges {
+
+		style := ""
+		if e.ref {
+			style = ", style=dashed"
+		}
+
+		_, _ = fmt.Fprintf(buf, "    %q -> %q [label=%q%s];\n",
+			e.from.Model().RestName,
+			e.to.Model().RestName,
+			e.label,
+			style,
+		)
+	}
+
+	_, _ = buf.WriteString("}\n")
+
+	return buf.String()
+}
+
+// keyAttributes returns the attributes that are worth drawing:
+// identifiers, keys and required attributes.
+func keyAttributes(s spec.Specification) []*spec.Attribute {
+
+	var out []*spec.Attribute
+
+	for _, attr := range s.Attributes(s.LatestAttributesVersion()) {
+		if attr.Identifier || attr.PrimaryKey || attr.ForeignKey || attr.Required {
+			out = append(out, attr)
+		}
+	}
+
+	return out
+}
+
+func actions(rel *spec.Relation) []string {
+
+	var out []string
+
+	if rel.Get != nil {
+		out = append(out, "get")
+	}
+	if rel.Create != nil {
+		out = append(out, "create")
+	}
+	if rel.Update != nil {
+		out = append(out, "update")
+	}
+	if rel.Delete != nil {
+		out = append(out, "delete")
+	}
+
+	return out
+}
+
+func typeName(attr *spec.Attribute) string {
+
+	switch attr.Type {
+	case spec.AttributeTypeExt:
+		return attr.SubType
+	case spec.AttributeTypeList, spec.AttributeTypeRefList:
+		return "[]" + attr.SubType
+	case spec.AttributeTypeMap, spec.AttributeTypeRefMap:
+		return "map[string]" + attr.SubType
+	case spec.AttributeTypeRef:
+		return attr.SubType
+	default:
+		return string(attr.Type)
+	}
+}
+
+// mermaidType returns a type name using only the
+// characters allowed by mermaid in attribute types.
+func mermaidType(attr *spec.Attribute) string {
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		case r == '[', r == ']':
+			return -1
+		default:
+			return '_'
+		}
+	}, strings.Replace(typeName(attr), "[]", "list_", 1))
+}
+
+func escapeRecord(s string) string {
+
+	return strings.NewReplacer(
+		`{`, `\{`,
+		`}`, `\}`,
+		`|`, `\|`,
+		`<`, `\<`,
+		`>`, `\>`,
+		`"`, `\"`,
+	).Replace(s)
+}
+
+func contains(list []string, value string) bool {
+
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
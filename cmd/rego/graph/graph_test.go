@@ -0,0 +1,245 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"go.aporeto.io/regolithe/spec"
+)
+
+const testRoot = `model:
+  rest_name: root
+  resource_name: root
+  entity_name: Root
+  package: root
+  group: core
+  description: Root object.
+  root: true
+
+relations:
+- rest_name: project
+  get:
+    description: Retrieves the projects.
+`
+
+const testProject = `model:
+  rest_name: project
+  resource_name: projects
+  entity_name: Project
+  package: project
+  group: core
+  description: A project.
+
+attributes:
+  v1:
+  - name: ID
+    description: The identifier.
+    type: string
+    exposed: true
+    identifier: true
+    read_only: true
+    autogenerated: true
+
+relations:
+- rest_name: task
+  get:
+    description: Retrieves the tasks.
+  create:
+    description: Creates a task.
+`
+
+const testTask = `model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  package: task
+  group: work
+  description: A task.
+
+attributes:
+  v1:
+  - name: owner
+    description: The owner.
+    type: ref
+    subtype: user
+    exposed: true
+`
+
+const testUser = `model:
+  rest_name: user
+  resource_name: users
+  entity_name: User
+  package: user
+  group: work
+  description: A user.
+  private: true
+`
+
+func loadTestSet(t *testing.T) spec.SpecificationSet {
+
+	dir := t.TempDir()
+
+	ini, err := os.ReadFile("../../../spec/tests/regolithe.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range map[string]string{
+		"regolithe.ini": string(ini),
+		"root.spec":     testRoot,
+		"project.spec":  testProject,
+		"task.spec":     testTask,
+		"user.spec":     testUser,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	set, err := spec.LoadSpecificationSet(dir, nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return set
+}
+
+func TestGraph_Write(t *testing.T) {
+
+	set := loadTestSet(t)
+
+	Convey("Given I write the mermaid graph of a set", t, func() {
+
+		buf := &bytes.Buffer{}
+		err := Write(buf, set, "mermaid", Filter{})
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the output should be correct", func() {
+			So(buf.String(), ShouldEqual, `erDiagram
+    Project {
+        string ID PK
+    }
+    Root
+    Task
+    User
+    Project ||--o{ Task : "get, create"
+    Root ||--o{ Project : "get"
+    Task }o..o| User : "owner"
+`)
+		})
+	})
+
+	Convey("Given I write the dot graph of a set", t, func() {
+
+		buf := &bytes.Buffer{}
+		err := Write(buf, set, "dot", Filter{})
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the output should be correct", func() {
+			So(buf.String(), ShouldEqual, `digraph specifications {
+    node [shape=record];
+    "project" [label="{Project|ID: string (PK)}"];
+    "root" [label="{Root}"];
+    "task" [label="{Task}"];
+    "user" [label="{User}", style=dashed];
+    "project" -> "task" [label="get, create"];
+    "root" -> "project" [label="get"];
+    "task" -> "user" [label="owner", style=dashed];
+}
+`)
+		})
+	})
+
+	Convey("Given I write the graph of a set in an unsupported format", t, func() {
+
+		err := Write(&bytes.Buffer{}, set, "svg", Filter{})
+
+		Convey("Then err should be correct", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unsupported graph format: svg")
+		})
+	})
+}
+
+func TestGraph_Filter(t *testing.T) {
+
+	set := loadTestSet(t)
+
+	nodes := func(filter Filter) []string {
+
+		g, err := newGraph(set, filter)
+		So(err, ShouldBeNil)
+
+		out := make([]string, len(g.nodes))
+		for i, n := range g.nodes {
+			out[i] = n.Model().RestName
+		}
+
+		return out
+	}
+
+	Convey("Given I filter the graph by group", t, func() {
+
+		Convey("Then only the specifications of the group should be drawn", func() {
+			So(nodes(Filter{Groups: []string{"work"}}), ShouldResemble, []string{"task", "user"})
+		})
+	})
+
+	Convey("Given I hide the private specifications", t, func() {
+
+		Convey("Then the private specifications should not be drawn", func() {
+			So(nodes(Filter{HidePrivate: true}), ShouldResemble, []string{"project", "root", "task"})
+		})
+
+		Convey("Then the edges to them should not be drawn", func() {
+			g, err := newGraph(set, Filter{HidePrivate: true})
+			So(err, ShouldBeNil)
+			So(len(g.edges), ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given I walk the graph from a specification", t, func() {
+
+		Convey("Then every reachable specification should be drawn without depth", func() {
+			So(nodes(Filter{From: "project"}), ShouldResemble, []string{"project", "task", "user"})
+		})
+
+		Convey("Then only the close specifications should be drawn with a depth", func() {
+			So(nodes(Filter{From: "root", Depth: 1}), ShouldResemble, []string{"project", "root"})
+		})
+
+		Convey("Then the walk should stop at the filtered out specifications", func() {
+			So(nodes(Filter{From: "project", HidePrivate: true}), ShouldResemble, []string{"project", "task"})
+		})
+	})
+
+	Convey("Given I walk the graph from a filtered out specification", t, func() {
+
+		_, err := newGraph(set, Filter{From: "root", Groups: []string{"work"}})
+
+		Convey("Then err should be correct", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to find specification 'root' in the selected specifications")
+		})
+	})
+}
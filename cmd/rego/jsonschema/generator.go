@@ -15,16 +15,27 @@ import (
 	"go.aporeto.io/regolithe/spec"
 )
 
-// Generate generates the json schema
+// Generate generates the json schema. If publicMode is
+// true, the private specifications are not generated.
 func Generate(set spec.SpecificationSet, outFolder string, publicMode bool) error {
 
-	if err := writeModel(set, outFolder, publicMode); err != nil {
+	if publicMode {
+		sel, err := spec.ParseSelector(spec.SelectorPublic)
+		if err != nil {
+			return err
+		}
+		if set, err = spec.Select(set, sel); err != nil {
+			return err
+		}
+	}
+
+	if err := writeModel(set, outFolder); err != nil {
 		return err
 	}
 
-	if err := writeGlobalResources(set, outFolder, publicMode); err != nil {
+	if err := writeGlobalResources(set, outFolder); err != nil {
 		return err
 	}
 
-	return writeGlobalResourceLists(set, outFolder, publicMode)
+	return writeGlobalResourceLists(set, outFolder)
 }
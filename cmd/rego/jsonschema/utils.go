@@ -39,7 +39,7 @@ func convertType(t spec.AttributeType) string {
 		return "object"
 	case spec.AttributeTypeRefList, spec.AttributeTypeList:
 		return "$list"
-	case spec.AttributeTypeRefMap:
+	case spec.AttributeTypeRefMap, spec.AttributeTypeMap:
 		return "$map"
 	case spec.AttributeTypeRef:
 		return "$ref"
@@ -30,7 +30,7 @@ var functions = template.FuncMap{
 	"stripFirstLevelBrackets": stripFirstLevelBrackets,
 }
 
-func writeGlobalResources(set spec.SpecificationSet, outFolder string, publicMode bool) error {
+func writeGlobalResources(set spec.SpecificationSet, outFolder string) error {
 
 	if err := os.MkdirAll(outFolder, 0750); err != nil && !os.IsExist(err) {
 		return err
@@ -46,13 +46,11 @@ func writeGlobalResources(set spec.SpecificationSet, outFolder string, publicMod
 	if err = tmpl.Execute(
 		&buf,
 		struct {
-			PublicMode bool
-			Name       string
-			Set        spec.SpecificationSet
+			Name string
+			Set  spec.SpecificationSet
 		}{
-			PublicMode: publicMode,
-			Name:       set.Configuration().Name,
-			Set:        set,
+			Name: set.Configuration().Name,
+			Set:  set,
 		}); err != nil {
 		return fmt.Errorf("unable to generate global resource code: %s", err)
 	}
@@ -70,7 +68,7 @@ func writeGlobalResources(set spec.SpecificationSet, outFolder string, publicMod
 	return writeFile(path.Join(outFolder, "_models.json"), out)
 }
 
-func writeGlobalResourceLists(set spec.SpecificationSet, outFolder string, publicMode bool) error {
+func writeGlobalResourceLists(set spec.SpecificationSet, outFolder string) error {
 
 	if err := os.MkdirAll(outFolder, 0750); err != nil && !os.IsExist(err) {
 		return err
@@ -86,13 +84,11 @@ func writeGlobalResourceLists(set spec.SpecificationSet, outFolder string, publi
 	if err = tmpl.Execute(
 		&buf,
 		struct {
-			PublicMode bool
-			Name       string
-			Set        spec.SpecificationSet
+			Name string
+			Set  spec.SpecificationSet
 		}{
-			PublicMode: publicMode,
-			Name:       set.Configuration().Name,
-			Set:        set,
+			Name: set.Configuration().Name,
+			Set:  set,
 		}); err != nil {
 		return fmt.Errorf("unable to generate global resource lists code: %s", err)
 	}
@@ -110,7 +106,7 @@ func writeGlobalResourceLists(set spec.SpecificationSet, outFolder string, publi
 	return writeFile(path.Join(outFolder, "_lists.json"), out)
 }
 
-func writeModel(set spec.SpecificationSet, outFolder string, publicMode bool) error {
+func writeModel(set spec.SpecificationSet, outFolder string) error {
 
 	if err := os.MkdirAll(outFolder, 0750); err != nil && !os.IsExist(err) {
 		return err
@@ -124,20 +120,14 @@ func writeModel(set spec.SpecificationSet, outFolder string, publicMode bool) er
 	for _, s := range set.Specifications() {
 		var buf bytes.Buffer
 
-		if publicMode && s.Model().Private {
-			continue
-		}
-
 		if err = tmpl.Execute(
 			&buf,
 			struct {
-				PublicMode bool
-				Name       string
-				Spec       spec.Specification
+				Name string
+				Spec spec.Specification
 			}{
-				PublicMode: publicMode,
-				Name:       set.Configuration().Name,
-				Spec:       s,
+				Name: set.Configuration().Name,
+				Spec: s,
 			}); err != nil {
 			return fmt.Errorf("unable to generate model code: %s", err)
 		}
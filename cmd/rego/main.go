@@ -12,15 +12,23 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.aporeto.io/regolithe/cmd/rego/diff"
 	"go.aporeto.io/regolithe/cmd/rego/doc"
+	"go.aporeto.io/regolithe/cmd/rego/graph"
 	"go.aporeto.io/regolithe/cmd/rego/jsonschema"
 	"go.aporeto.io/regolithe/cmd/rego/specset"
+	"go.aporeto.io/regolithe/cmd/rego/unused"
 	"go.aporeto.io/regolithe/spec"
 )
 
@@ -52,14 +60,41 @@ func main() {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 
+			if viper.GetString("select") != "" && viper.Get("mode") != "spec" {
+				return fmt.Errorf("unable to format: --select can only be used with the spec mode")
+			}
+
 			switch viper.Get("mode") {
 			case "spec":
-				s := spec.NewSpecification()
+				var opts []spec.SpecificationOption
+				if viper.GetBool("preserve-order") {
+					opts = append(opts, spec.SpecificationOptionPreserveOrder())
+				}
+
+				selector, err := spec.ParseSelector(viper.GetString("select"))
+				if err != nil {
+					return fmt.Errorf("unable to format: %s", err)
+				}
 
-				if err := s.Read(os.Stdin, true); err != nil {
+				data, err := io.ReadAll(os.Stdin)
+				if err != nil {
 					return fmt.Errorf("unable to format: unable to read spec: %s", err)
 				}
 
+				s := spec.NewSpecification(opts...)
+
+				if err := s.Read(bytes.NewReader(data), true); err != nil {
+					return fmt.Errorf("unable to format: unable to read spec: %s", err)
+				}
+
+				// Specifications that are not selected are printed unchanged.
+				if !selector(s) {
+					if _, err := os.Stdout.Write(data); err != nil {
+						return fmt.Errorf("unable to format: unable to write spec: %s", err)
+					}
+					return nil
+				}
+
 				if err := s.Write(os.Stdout); err != nil {
 					return fmt.Errorf("unable to format: unable to write spec: %s", err)
 				}
@@ -96,12 +131,25 @@ func main() {
 				if err := pm.Write(os.Stdout); err != nil {
 					return fmt.Errorf("unable to format: unable to write parametermapping: %s", err)
 				}
+
+			case "enummapping":
+				em := spec.NewEnumMapping()
+
+				if err := em.Read(os.Stdin, true); err != nil {
+					return fmt.Errorf("unable to format: unable to read enummapping: %s", err)
+				}
+
+				if err := em.Write(os.Stdout); err != nil {
+					return fmt.Errorf("unable to format: unable to write enummapping: %s", err)
+				}
 			}
 
 			return nil
 		},
 	}
-	formatCmd.Flags().StringP("mode", "m", "spec", "Mode of formatting. Can be spec, typemapping, validationmapping, parametermapping.")
+	formatCmd.Flags().StringP("mode", "m", "spec", "Mode of formatting. Can be spec, typemapping, validationmapping, parametermapping, enummapping.")
+	formatCmd.Flags().Bool("preserve-order", false, "If set, the attributes, relations and parameters of a spec keep their declared order.")
+	formatCmd.Flags().String("select", "", "Only format the specification if it matches the given selector, like 'group=core,!private'.")
 
 	var docCmd = &cobra.Command{
 		Use:           "doc",
@@ -113,11 +161,13 @@ func main() {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			s, err := spec.LoadSpecificationSet(
-				viper.GetString("dir"),
-				nil,
-				nil,
-				viper.GetString("category"),
+			s, err := spec.Load(
+				cmd.Context(),
+				spec.NewDirectorySource(viper.GetString("dir")),
+				spec.OptionLogger(slog.Default()),
+				spec.OptionMappingMode(viper.GetString("category")),
+				spec.OptionOverlays(viper.GetStringSlice("overlay")...),
+				spec.OptionSelect(viper.GetString("select")),
 			)
 			if err != nil {
 				return fmt.Errorf("unable to load specification set: %s", err)
@@ -132,6 +182,8 @@ func main() {
 	}
 	docCmd.Flags().StringP("dir", "d", "", "Path of the specifications folder.")
 	docCmd.Flags().String("format", "markdown", "Path of the specifications folder.")
+	docCmd.Flags().StringSlice("overlay", nil, "Path of an overlay folder patching the specifications. Can be repeated.")
+	docCmd.Flags().String("select", "", "Only use the specifications matching the given selector, like 'group=core,!private'.")
 
 	var jsonSchemaCmd = &cobra.Command{
 		Use:           "jsonschema",
@@ -143,11 +195,13 @@ func main() {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			s, err := spec.LoadSpecificationSet(
-				viper.GetString("dir"),
-				nil,
-				nil,
-				"jsonschema",
+			s, err := spec.Load(
+				cmd.Context(),
+				spec.NewDirectorySource(viper.GetString("dir")),
+				spec.OptionLogger(slog.Default()),
+				spec.OptionMappingMode("jsonschema"),
+				spec.OptionOverlays(viper.GetStringSlice("overlay")...),
+				spec.OptionSelect(viper.GetString("select")),
 			)
 			if err != nil {
 				return err
@@ -158,7 +212,282 @@ func main() {
 	}
 	jsonSchemaCmd.Flags().StringP("dir", "d", "", "Path of the specifications folder.")
 	jsonSchemaCmd.Flags().StringP("out", "o", "./codegen", "Path where to write the json files.")
-	jsonSchemaCmd.Flags().BoolP("public", "p", false, "If set to true, only exposed attributes and public objects will be generated.")
+	jsonSchemaCmd.Flags().BoolP("public", "p", false, "If set to true, only exposed attributes and public objects will be generated. Same as --select '!private'.")
+	jsonSchemaCmd.Flags().StringSlice("overlay", nil, "Path of an overlay folder patching the specifications. Can be repeated.")
+	jsonSchemaCmd.Flags().String("select", "", "Only use the specifications matching the given selector, like 'group=core,!private'.")
+
+	var graphCmd = &cobra.Command{
+		Use:           "graph",
+		Short:         "Generate a relationship diagram of the given specification set",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			s, err := spec.Load(
+				cmd.Context(),
+				spec.NewDirectorySource(viper.GetString("dir")),
+				spec.OptionLogger(slog.Default()),
+				spec.OptionSelect(viper.GetString("select")),
+			)
+			if err != nil {
+				return fmt.Errorf("unable to load specification set: %s", err)
+			}
+
+			if err := graph.Write(
+				os.Stdout,
+				s,
+				viper.GetString("format"),
+				graph.Filter{
+					Groups:      viper.GetStringSlice("group"),
+					From:        viper.GetString("from"),
+					Depth:       viper.GetInt("depth"),
+					HidePrivate: viper.GetBool("hide-private"),
+				},
+			); err != nil {
+				return fmt.Errorf("unable to write graph: %s", err)
+			}
+
+			return nil
+		},
+	}
+	graphCmd.Flags().StringP("dir", "d", "", "Path of the specifications folder.")
+	graphCmd.Flags().String("format", "mermaid", "Format of the graph. Can be mermaid or dot.")
+	graphCmd.Flags().StringSliceP("group", "g", nil, "Only draw the specifications in the given groups.")
+	graphCmd.Flags().StringP("from", "f", "", "Only draw the specifications reachable from the given rest name.")
+	graphCmd.Flags().Int("depth", 0, "Maximum distance from the specification given by --from. 0 means no limit.")
+	graphCmd.Flags().Bool("hide-private", false, "If set, private specifications will not be drawn.")
+	graphCmd.Flags().String("select", "", "Only use the specifications matching the given selector, like 'group=core,!private'.")
+
+	var diffCmd = &cobra.Command{
+		Use:           "diff <old> <new>",
+		Short:         "Detect changes between two specification sets",
+		Long:          "Compare two specification sets and classify the changes. Each argument can be a folder or a git revision. Exits with an error if there are breaking changes.",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			if len(args) != 2 {
+				return fmt.Errorf("usage: diff <old> <new>")
+			}
+
+			oldSet, err := diff.Load(args[0], viper.GetString("repo"), viper.GetString("path"), viper.GetString("select"))
+			if err != nil {
+				return fmt.Errorf("unable to load old specification set: %s", err)
+			}
+
+			newSet, err := diff.Load(args[1], viper.GetString("repo"), viper.GetString("path"), viper.GetString("select"))
+			if err != nil {
+				return fmt.Errorf("unable to load new specification set: %s", err)
+			}
+
+			return diff.Write(os.Stdout, spec.DiffSpecificationSets(oldSet, newSet), viper.GetString("format"))
+		},
+	}
+	diffCmd.Flags().StringP("repo", "r", ".", "Path of the git repository used to resolve revisions.")
+	diffCmd.Flags().StringP("path", "p", "", "Path of the specifications folder in the repository when using revisions.")
+	diffCmd.Flags().String("format", "text", "Format of the report. Can be text or json.")
+	diffCmd.Flags().String("select", "", "Only compare the specifications matching the given selector, like 'group=core,!private'.")
+
+	var lintCmd = &cobra.Command{
+		Use:           "lint",
+		Short:         "Check the style of the given specification set",
+		Long:          "Run the lint rules over the given specification set. Rule severities can be changed in the [lint] section of regolithe.ini. Exits with an error if an issue has the error severity.",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			if viper.GetBool("rules") {
+				for _, r := range spec.LintRules() {
+					fmt.Printf("%-32s %-8s %s\n", r.ID, r.Severity, r.Description)
+				}
+				return nil
+			}
+
+			issues, err := spec.LintSpecificationSet(viper.GetString("dir"), spec.OptionSelect(viper.GetString("select")))
+			if err != nil {
+				return fmt.Errorf("unable to load specification set: %s", err)
+			}
+
+			var nErrors int
+			for _, issue := range issues {
+
+				if issue.Severity == spec.LintSeverityError {
+					nErrors++
+				}
+
+				if issue.RuleID == "" {
+					fmt.Printf("%s: %s\n", issue.Severity, issue)
+					continue
+				}
+
+				fmt.Printf("%s: [%s] %s\n", issue.Severity, issue.RuleID, issue)
+			}
+
+			if nErrors > 0 {
+				return fmt.Errorf("%d lint error(s) found", nErrors)
+			}
+
+			return nil
+		},
+	}
+	lintCmd.Flags().StringP("dir", "d", "", "Path of the specifications folder.")
+	lintCmd.Flags().Bool("rules", false, "If set, list the available lint rules and exit.")
+	lintCmd.Flags().String("select", "", "Only report the issues of the specifications matching the given selector, like 'group=core,!private'.")
+
+	var mappingsCmd = &cobra.Command{
+		Use:           "mappings",
+		Short:         "Check the type and validation mappings of the given specification set for a mode",
+		Long:          "List the external types and validations used by the specification set that are missing or ignored for the given mode. Exits with an error if a mapping is missing.",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			mode := viper.GetString("mode")
+			if mode == "" {
+				return fmt.Errorf("--mode is required")
+			}
+
+			s, err := spec.Load(
+				cmd.Context(),
+				spec.NewDirectorySource(viper.GetString("dir")),
+				spec.OptionLogger(slog.Default()),
+				spec.OptionSelect(viper.GetString("select")),
+			)
+			if err != nil {
+				return fmt.Errorf("unable to load specification set: %s", err)
+			}
+
+			var nMissing int
+			for _, issue := range spec.CheckMappings(s, mode) {
+
+				if issue.Status == spec.MappingStatusMissing {
+					nMissing++
+				}
+
+				fmt.Println(issue)
+			}
+
+			if nMissing > 0 {
+				return fmt.Errorf("%d mapping(s) missing for mode '%s'", nMissing, mode)
+			}
+
+			return nil
+		},
+	}
+	mappingsCmd.Flags().StringP("dir", "d", "", "Path of the specifications folder.")
+	mappingsCmd.Flags().StringP("mode", "m", "", "Mode to check the mappings for.")
+	mappingsCmd.Flags().String("select", "", "Only use the specifications matching the given selector, like 'group=core,!private'.")
+
+	var unusedCmd = &cobra.Command{
+		Use:           "unused",
+		Short:         "Report the unreferenced abstracts, mappings, global parameters and named enums of the given specification set",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			dir := viper.GetString("dir")
+
+			// Definitions only used by the specifications that are
+			// not selected would be reported, so they cannot be pruned.
+			if viper.GetBool("prune") && viper.GetString("select") != "" {
+				return fmt.Errorf("--prune cannot be used with --select")
+			}
+
+			s, err := spec.Load(
+				cmd.Context(),
+				spec.NewDirectorySource(dir),
+				spec.OptionLogger(slog.Default()),
+				spec.OptionSelect(viper.GetString("select")),
+			)
+			if err != nil {
+				return fmt.Errorf("unable to load specification set: %s", err)
+			}
+
+			u := spec.FindUnused(s)
+
+			unused.Write(os.Stdout, u)
+
+			if !viper.GetBool("prune") || u.Empty() {
+				return nil
+			}
+
+			if err := unused.Prune(dir, u); err != nil {
+				return fmt.Errorf("unable to prune unused definitions: %s", err)
+			}
+
+			fmt.Println("Unused definitions have been pruned.")
+
+			return nil
+		},
+	}
+	unusedCmd.Flags().StringP("dir", "d", "", "Path of the specifications folder.")
+	unusedCmd.Flags().Bool("prune", false, "If set, remove the unused definitions from the specifications folder.")
+	unusedCmd.Flags().String("select", "", "Only look for the definitions used by the specifications matching the given selector, like 'group=core,!private'.")
+
+	var vendorCmd = &cobra.Command{
+		Use:           "vendor",
+		Short:         "Fetch the dependencies of the given specification set and lock their versions",
+		Long:          "Fetch the dependencies declared in the regolithe.ini file into the vendor folder of the specification set, and write their resolved commits in the regolithe.lock file. Git dependencies are fetched at the locked commit unless --update is set.",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			opts := spec.VendorOptions{
+				Update: viper.GetBool("update"),
+				Logger: slog.Default(),
+			}
+
+			if !viper.GetBool("no-cache") {
+				cacheDir, err := os.UserCacheDir()
+				if err != nil {
+					return fmt.Errorf("unable to find cache folder: %s", err)
+				}
+				opts.GitOptions = append(opts.GitOptions, spec.GitOptionCacheDir(filepath.Join(cacheDir, "regolithe", "git")))
+			}
+
+			lock, err := spec.Vendor(cmd.Context(), viper.GetString("dir"), opts)
+			if err != nil {
+				return fmt.Errorf("unable to vendor dependencies: %s", err)
+			}
+
+			names := make([]string, 0, len(lock))
+			for name := range lock {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				version := lock[name].Commit
+				if version == "" {
+					version = lock[name].Path
+				}
+				fmt.Printf("%s: %s\n", name, version)
+			}
+
+			return nil
+		},
+	}
+	vendorCmd.Flags().StringP("dir", "d", "", "Path of the specifications folder.")
+	vendorCmd.Flags().Bool("update", false, "If set, ignore the locked commits and fetch the dependencies at their ref.")
+	vendorCmd.Flags().Bool("no-cache", false, "If set, the git repositories are not cached.")
 
 	var initCmd = &cobra.Command{
 		Use:           "init <dest>",
@@ -183,8 +512,14 @@ func main() {
 	rootCmd.AddCommand(
 		formatCmd,
 		docCmd,
+		diffCmd,
+		graphCmd,
 		initCmd,
 		jsonSchemaCmd,
+		lintCmd,
+		mappingsCmd,
+		unusedCmd,
+		vendorCmd,
 	)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -0,0 +1,203 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unused
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.aporeto.io/regolithe/spec"
+)
+
+// Write writes the given unused definitions as text.
+func Write(w io.Writer, unused *spec.Unused) {
+
+	if unused.Empty() {
+		_, _ = fmt.Fprintln(w, "No unused definitions.")
+		return
+	}
+
+	for _, section := range []struct {
+		title string
+		names []string
+	}{
+		{"Abstracts", unused.Abstracts},
+		{"Type mappings", unused.Types},
+		{"Validation functions", unused.Validations},
+		{"Global parameters", unused.Parameters},
+		{"Named enums", unused.Enums},
+	} {
+
+		if len(section.names) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(w, "%s (%d):\n\n", section.title, len(section.names))
+		for _, name := range section.names {
+			_, _ = fmt.Fprintf(w, "  - %s\n", name)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+// Prune removes the given unused definitions from the
+// specification set located in the given folder.
+func Prune(dir string, unused *spec.Unused) error {
+
+	if len(unused.Abstracts) > 0 {
+		if err := pruneAbstracts(dir, unused.Abstracts); err != nil {
+			return err
+		}
+	}
+
+	if err := pruneMapping(filepath.Join(dir, "_type.mapping"), unused.Types); err != nil {
+		return err
+	}
+
+	if err := pruneMapping(filepath.Join(dir, "_validation.mapping"), unused.Validations); err != nil {
+		return err
+	}
+
+	if err := pruneMapping(filepath.Join(dir, "_parameter.mapping"), unused.Parameters); err != nil {
+		return err
+	}
+
+	return pruneMapping(filepath.Join(dir, "_enums.mapping"), unused.Enums)
+}
+
+// pruneMapping removes the given names from the mapping file at the given
+// path. The mapping is read from the file rather than from the set, as the
+// mappings of the set also contain the entries of the dependencies. The
+// entries are removed from the text of the file, so the comments and the
+// layout of the remaining entries are preserved.
+func pruneMapping(path string, names []string) error {
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	out, removed, remaining := removeEntries(data, names)
+	if removed == 0 {
+		return nil
+	}
+
+	if remaining == 0 {
+		return os.Remove(path)
+	}
+
+	return os.WriteFile(path, out, 0600)
+}
+
+// removeEntries removes the top level entries with the given names from
+// the given YAML document. An entry spans from its key to the next line
+// starting at the first column, which may be a comment describing the
+// next entry. The comment lines right above a removed key are removed
+// with it. It returns the new document, the number of
+// removed entries and the number of remaining ones.
+func removeEntries(data []byte, names []string) (out []byte, removed int, remaining int) {
+
+	remove := map[string]struct{}{}
+	for _, name := range names {
+		remove[name] = struct{}{}
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	kept := make([]string, 0, len(lines))
+	skipping := false
+
+	for _, line := range lines {
+
+		trimmed := strings.TrimRight(line, "\r\n")
+		topLevel := trimmed != "" && trimmed[0] != ' ' && trimmed[0] != '\t' && trimmed[0] != '-'
+
+		if !topLevel {
+			if !skipping {
+				kept = append(kept, line)
+			}
+			continue
+		}
+
+		skipping = false
+
+		if strings.HasPrefix(trimmed, "#") {
+			kept = append(kept, line)
+			continue
+		}
+
+		key, _, _ := strings.Cut(trimmed, ":")
+		if _, ok := remove[strings.Trim(strings.TrimSpace(key), `'"`)]; !ok {
+			remaining++
+			kept = append(kept, line)
+			continue
+		}
+
+		removed++
+		skipping = true
+
+		for len(kept) > 0 && strings.HasPrefix(kept[len(kept)-1], "#") {
+			kept = kept[:len(kept)-1]
+		}
+	}
+
+	doc := strings.TrimRight(strings.Join(kept, ""), "\n")
+	if doc != "" {
+		doc += "\n"
+	}
+
+	return []byte(doc), removed, remaining
+}
+
+// pruneAbstracts removes the abstract files with the given names.
+func pruneAbstracts(dir string, names []string) error {
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	remove := map[string]struct{}{}
+	for _, name := range names {
+		remove[name] = struct{}{}
+	}
+
+	for _, e := range entries {
+
+		if e.IsDir() || filepath.Ext(e.Name()) != ".abs" {
+			continue
+		}
+
+		// Abstract names are computed the same way
+		// as in spec.LoadSpecificationSet.
+		name := strings.TrimPrefix(strings.TrimSuffix(e.Name(), ".abs"), "+")
+		if _, ok := remove[name]; !ok {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return fmt.Errorf("unable to remove abstract '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
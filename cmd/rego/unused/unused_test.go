@@ -0,0 +1,113 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unused
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"go.aporeto.io/regolithe/spec"
+)
+
+const testTypeMapping = `# Shared type mappings.
+
+# A list of integers.
+int_array:
+  test:
+    type: '[]int'  # the go type
+
+# A map of strings.
+string_map:
+  test:
+    type: map[string]string
+
+'toto':
+  test:
+    type: Toto
+`
+
+func TestUnused_Prune(t *testing.T) {
+
+	Convey("Given I prune a mapping file with comments", t, func() {
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "_type.mapping")
+		So(os.WriteFile(path, []byte(testTypeMapping), 0600), ShouldBeNil)
+
+		err := Prune(dir, &spec.Unused{Types: []string{"string_map", "toto", "missing"}})
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then only the unused entries and their comments should be removed", func() {
+			data, err := os.ReadFile(path)
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, `# Shared type mappings.
+
+# A list of integers.
+int_array:
+  test:
+    type: '[]int'  # the go type
+`)
+		})
+	})
+
+	Convey("Given I prune the first entry of a mapping file", t, func() {
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "_type.mapping")
+		So(os.WriteFile(path, []byte(testTypeMapping), 0600), ShouldBeNil)
+
+		err := Prune(dir, &spec.Unused{Types: []string{"int_array"}})
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the comments of the next entry should be kept", func() {
+			data, err := os.ReadFile(path)
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, `# Shared type mappings.
+
+# A map of strings.
+string_map:
+  test:
+    type: map[string]string
+
+'toto':
+  test:
+    type: Toto
+`)
+		})
+	})
+
+	Convey("Given I prune every entry of a mapping file", t, func() {
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "_type.mapping")
+		So(os.WriteFile(path, []byte(testTypeMapping), 0600), ShouldBeNil)
+
+		err := Prune(dir, &spec.Unused{Types: []string{"int_array", "string_map", "toto"}})
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the file should be removed", func() {
+			_, err := os.Stat(path)
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+	})
+}
@@ -14,6 +14,9 @@ package regolithe
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -21,7 +24,8 @@ import (
 	"go.aporeto.io/regolithe/spec"
 )
 
-// NewCommand generates a new CLI for regolith
+// NewCommand generates a new CLI for regolith.
+// The given LoadOptions are used to load every SpecificationSet.
 func NewCommand(
 	name string,
 	description string,
@@ -30,6 +34,7 @@ func NewCommand(
 	typeConvertFunc spec.AttributeTypeConverterFunc,
 	typeMappingName string,
 	generatorFunc func([]spec.SpecificationSet, string) error,
+	opts ...spec.LoadOption,
 ) *cobra.Command {
 
 	cobra.OnInitialize(func() {
@@ -69,12 +74,22 @@ func NewCommand(
 
 			var specSets []spec.SpecificationSet
 
+			loadOpts := append(
+				[]spec.LoadOption{
+					spec.OptionOverlays(viper.GetStringSlice("overlay")...),
+					spec.OptionSelect(viper.GetString("select")),
+					spec.OptionLogger(slog.Default()),
+				},
+				opts...,
+			)
+
 			for _, dir := range viper.GetStringSlice("dir") {
 				set, err := spec.LoadSpecificationSet(
 					dir,
 					nameConvertFunc,
 					typeConvertFunc,
 					typeMappingName,
+					loadOpts...,
 				)
 				if err != nil {
 					return err
@@ -87,6 +102,18 @@ func NewCommand(
 		},
 	}
 	cmdFolderGen.Flags().StringSliceP("dir", "d", nil, "Path of the specifications folder.")
+	cmdFolderGen.Flags().StringSlice("overlay", nil, "Path of an overlay folder patching the specifications. Can be repeated.")
+	cmdFolderGen.Flags().String("select", "", "Only generate the specifications matching the given selector, like 'group=core,!private'.")
+
+	remoteOpts := append(
+		[]spec.LoadOption{
+			spec.OptionNameConverter(nameConvertFunc),
+			spec.OptionTypeConverter(typeConvertFunc),
+			spec.OptionMappingMode(typeMappingName),
+			spec.OptionLogger(slog.Default()),
+		},
+		opts...,
+	)
 
 	var githubGen = &cobra.Command{
 		Use:           "github",
@@ -98,14 +125,15 @@ func NewCommand(
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			specSet, err := spec.LoadSpecificationSetFromGithub(
-				viper.GetString("token"),
-				viper.GetString("repo"),
-				viper.GetString("ref"),
-				viper.GetString("path"),
-				nameConvertFunc,
-				typeConvertFunc,
-				typeMappingName,
+			specSet, err := spec.Load(
+				cmd.Context(),
+				spec.NewGithubSource(
+					viper.GetString("token"),
+					viper.GetString("repo"),
+					viper.GetString("ref"),
+					viper.GetString("path"),
+				),
+				append([]spec.LoadOption{spec.OptionSelect(viper.GetString("select"))}, remoteOpts...)...,
 			)
 			if err != nil {
 				return err
@@ -118,11 +146,75 @@ func NewCommand(
 	githubGen.Flags().StringP("path", "p", "", "Internal path to a directory in the repo if not in the root.")
 	githubGen.Flags().StringP("ref", "R", "master", "Branch or tag to use.")
 	githubGen.Flags().StringP("token", "t", "", "The api token to use.")
+	githubGen.Flags().String("select", "", "Only generate the specifications matching the given selector, like 'group=core,!private'.")
+
+	var gitGen = &cobra.Command{
+		Use:           "git",
+		Short:         "Generate the model using a remote git repository.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			if viper.GetString("url") == "" {
+				return errors.New("--url is required")
+			}
+
+			var gitOpts []spec.GitSourceOption
+
+			if token := viper.GetString("token"); token != "" {
+				gitOpts = append(gitOpts, spec.GitOptionToken(token))
+			}
+
+			if key := viper.GetString("ssh-key"); key != "" {
+				gitOpts = append(gitOpts, spec.GitOptionSSHKey(key, viper.GetString("ssh-key-passphrase")))
+			}
+
+			if !viper.GetBool("no-cache") {
+				cacheDir, err := os.UserCacheDir()
+				if err != nil {
+					return fmt.Errorf("unable to find cache folder: %w", err)
+				}
+				gitOpts = append(gitOpts, spec.GitOptionCacheDir(filepath.Join(cacheDir, "regolithe", "git")))
+			}
+
+			specSet, err := spec.Load(
+				cmd.Context(),
+				spec.NewGitSource(
+					viper.GetString("url"),
+					viper.GetString("ref"),
+					viper.GetString("path"),
+					gitOpts...,
+				),
+				append([]spec.LoadOption{spec.OptionSelect(viper.GetString("select"))}, remoteOpts...)...,
+			)
+			if err != nil {
+				return err
+			}
+
+			if p, ok := specSet.(spec.CommitProvider); ok {
+				slog.Info("Loaded specifications", "commit", p.Commit())
+			}
+
+			return generatorFunc([]spec.SpecificationSet{specSet}, viper.GetString("out"))
+		},
+	}
+	gitGen.Flags().StringP("url", "u", "", "URL of the git repository. Can use https, ssh or file.")
+	gitGen.Flags().StringP("path", "p", "", "Internal path to a directory in the repo if not in the root.")
+	gitGen.Flags().StringP("ref", "R", "master", "Branch, tag or commit to use. Commits can be abbreviated.")
+	gitGen.Flags().StringP("token", "t", "", "The token to use for HTTP authentication.")
+	gitGen.Flags().StringP("ssh-key", "k", "", "Path of the private key to use for SSH authentication. The SSH agent is used if not set.")
+	gitGen.Flags().String("ssh-key-passphrase", "", "Passphrase of the SSH private key.")
+	gitGen.Flags().Bool("no-cache", false, "If set, the repository is not cached.")
+	gitGen.Flags().String("select", "", "Only generate the specifications matching the given selector, like 'group=core,!private'.")
 
 	rootCmd.AddCommand(
 		versionCmd,
 		cmdFolderGen,
 		githubGen,
+		gitGen,
 	)
 
 	return rootCmd
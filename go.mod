@@ -1,6 +1,6 @@
 module go.aporeto.io/regolithe
 
-go 1.18
+go 1.21
 
 require (
 	github.com/araddon/dateparse v0.0.0-20210429162001-6b43995a97de
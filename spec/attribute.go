@@ -13,6 +13,11 @@ package spec
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"time"
+	"unicode/utf8"
 )
 
 // AttributeType represents the various type for an attribute.
@@ -26,6 +31,7 @@ const (
 	AttributeTypeBool    AttributeType = "boolean"
 	AttributeTypeEnum    AttributeType = "enum"
 	AttributeTypeList    AttributeType = "list"
+	AttributeTypeMap     AttributeType = "map"
 	AttributeTypeObject  AttributeType = "object"
 	AttributeTypeTime    AttributeType = "time"
 	AttributeTypeExt     AttributeType = "external"
@@ -40,41 +46,53 @@ type Attribute struct {
 	// NOTE: Order of attributes matters!
 	// The YAML will be dumped respecting this order.
 
-	Name                string         `yaml:"name,omitempty"                   json:"name,omitempty"`
-	ExposedName         string         `yaml:"exposed_name,omitempty"           json:"exposed_name,omitempty"`
-	Description         string         `yaml:"description,omitempty"            json:"description,omitempty"`
-	Type                AttributeType  `yaml:"type,omitempty"                   json:"type,omitempty"`
-	Exposed             bool           `yaml:"exposed,omitempty"                json:"exposed,omitempty"`
-	SubType             string         `yaml:"subtype,omitempty"                json:"subtype,omitempty"`
-	Stored              bool           `yaml:"stored,omitempty"                 json:"stored,omitempty"`
-	Required            bool           `yaml:"required,omitempty"               json:"required,omitempty"`
-	ReadOnly            bool           `yaml:"read_only,omitempty"              json:"read_only,omitempty"`
-	CreationOnly        bool           `yaml:"creation_only,omitempty"          json:"creation_only,omitempty"`
-	AllowedChars        string         `yaml:"allowed_chars,omitempty"          json:"allowed_chars,omitempty"`
-	AllowedCharsMessage string         `yaml:"allowed_chars_message,omitempty"  json:"allowed_chars_message,omitempty"`
-	AllowedChoices      []string       `yaml:"allowed_choices,omitempty"        json:"allowed_choices,omitempty"`
-	Autogenerated       bool           `yaml:"autogenerated,omitempty"          json:"autogenerated,omitempty"`
-	DefaultValue        any            `yaml:"default_value,omitempty"          json:"default_value,omitempty"`
-	Deprecated          bool           `yaml:"deprecated,omitempty"             json:"deprecated,omitempty"`
-	ExampleValue        any            `yaml:"example_value,omitempty"          json:"example_value,omitempty"`
-	Filterable          bool           `yaml:"filterable,omitempty"             json:"filterable,omitempty"`
-	ForeignKey          bool           `yaml:"foreign_key,omitempty"            json:"foreign_key,omitempty"`
-	Getter              bool           `yaml:"getter,omitempty"                 json:"getter,omitempty"`
-	Setter              bool           `yaml:"setter,omitempty"                 json:"setter,omitempty"`
-	Identifier          bool           `yaml:"identifier,omitempty"             json:"identifier,omitempty"`
-	MaxLength           uint16         `yaml:"max_length,omitempty"             json:"max_length,omitempty"`
-	MinLength           uint16         `yaml:"min_length,omitempty"             json:"min_length,omitempty"`
-	MaxValue            float64        `yaml:"max_value,omitempty"              json:"max_value,omitempty"`
-	MinValue            float64        `yaml:"min_value,omitempty"              json:"min_value,omitempty"`
-	Orderable           bool           `yaml:"orderable,omitempty"              json:"orderable,omitempty"`
-	PrimaryKey          bool           `yaml:"primary_key,omitempty"            json:"primary_key,omitempty"`
-	Secret              bool           `yaml:"secret,omitempty"                 json:"secret,omitempty"`
-	Transient           bool           `yaml:"transient,omitempty"              json:"transient,omitempty"`
-	OmitEmpty           bool           `yaml:"omit_empty,omitempty"             json:"omit_empty,omitempty"`
-	Encrypted           bool           `yaml:"encrypted,omitempty"              json:"encrypted,omitempty"`
-	Signed              bool           `yaml:"signed,omitempty"                 json:"signed,omitempty"`
-	Validations         []string       `yaml:"validations,omitempty"            json:"validations,omitempty"`
-	Extensions          map[string]any `yaml:"extensions,omitempty"             json:"extensions,omitempty"`
+	Name                   string           `yaml:"name,omitempty"                       json:"name,omitempty"`
+	ExposedName            string           `yaml:"exposed_name,omitempty"               json:"exposed_name,omitempty"`
+	Description            string           `yaml:"description,omitempty"                json:"description,omitempty"`
+	Type                   AttributeType    `yaml:"type,omitempty"                       json:"type,omitempty"`
+	Exposed                bool             `yaml:"exposed,omitempty"                    json:"exposed,omitempty"`
+	SubType                string           `yaml:"subtype,omitempty"                    json:"subtype,omitempty"`
+	Stored                 bool             `yaml:"stored,omitempty"                     json:"stored,omitempty"`
+	Required               bool             `yaml:"required,omitempty"                   json:"required,omitempty"`
+	ReadOnly               bool             `yaml:"read_only,omitempty"                  json:"read_only,omitempty"`
+	CreationOnly           bool             `yaml:"creation_only,omitempty"              json:"creation_only,omitempty"`
+	AllowedChars           string           `yaml:"allowed_chars,omitempty"              json:"allowed_chars,omitempty"`
+	AllowedCharsMessage    string           `yaml:"allowed_chars_message,omitempty"      json:"allowed_chars_message,omitempty"`
+	AllowedChoices         []string         `yaml:"allowed_choices,omitempty"            json:"allowed_choices,omitempty"`
+	EnumValues             []*EnumValue     `yaml:"enum_values,omitempty"                json:"enum_values,omitempty"`
+	EnumReference          string           `yaml:"enum_reference,omitempty"             json:"enum_reference,omitempty"`
+	Autogenerated          bool             `yaml:"autogenerated,omitempty"              json:"autogenerated,omitempty"`
+	DefaultValue           any              `yaml:"default_value,omitempty"              json:"default_value,omitempty"`
+	Deprecated             bool             `yaml:"deprecated,omitempty"                 json:"deprecated,omitempty"`
+	ExampleValue           any              `yaml:"example_value,omitempty"              json:"example_value,omitempty"`
+	Filterable             bool             `yaml:"filterable,omitempty"                 json:"filterable,omitempty"`
+	ForeignKey             bool             `yaml:"foreign_key,omitempty"                json:"foreign_key,omitempty"`
+	Getter                 bool             `yaml:"getter,omitempty"                     json:"getter,omitempty"`
+	Setter                 bool             `yaml:"setter,omitempty"                     json:"setter,omitempty"`
+	Identifier             bool             `yaml:"identifier,omitempty"                 json:"identifier,omitempty"`
+	MaxLength              *uint16          `yaml:"max_length,omitempty"                 json:"max_length,omitempty"`
+	MinLength              *uint16          `yaml:"min_length,omitempty"                 json:"min_length,omitempty"`
+	MaxValue               *float64         `yaml:"max_value,omitempty"                  json:"max_value,omitempty"`
+	ExclusiveMaxValue      bool             `yaml:"exclusive_max_value,omitempty"        json:"exclusive_max_value,omitempty"`
+	MinValue               *float64         `yaml:"min_value,omitempty"                  json:"min_value,omitempty"`
+	ExclusiveMinValue      bool             `yaml:"exclusive_min_value,omitempty"        json:"exclusive_min_value,omitempty"`
+	MultipleOf             *float64         `yaml:"multiple_of,omitempty"                json:"multiple_of,omitempty"`
+	MaxItems               *uint16          `yaml:"max_items,omitempty"                  json:"max_items,omitempty"`
+	MinItems               *uint16          `yaml:"min_items,omitempty"                  json:"min_items,omitempty"`
+	UniqueItems            bool             `yaml:"unique_items,omitempty"               json:"unique_items,omitempty"`
+	Items                  *ItemConstraints `yaml:"items,omitempty"                      json:"items,omitempty"`
+	KeyAllowedChars        string           `yaml:"key_allowed_chars,omitempty"          json:"key_allowed_chars,omitempty"`
+	KeyAllowedCharsMessage string           `yaml:"key_allowed_chars_message,omitempty"  json:"key_allowed_chars_message,omitempty"`
+	Orderable              bool             `yaml:"orderable,omitempty"                  json:"orderable,omitempty"`
+	PrimaryKey             bool             `yaml:"primary_key,omitempty"                json:"primary_key,omitempty"`
+	Secret                 bool             `yaml:"secret,omitempty"                     json:"secret,omitempty"`
+	Transient              bool             `yaml:"transient,omitempty"                  json:"transient,omitempty"`
+	OmitEmpty              bool             `yaml:"omit_empty,omitempty"                 json:"omit_empty,omitempty"`
+	Encrypted              bool             `yaml:"encrypted,omitempty"                  json:"encrypted,omitempty"`
+	Signed                 bool             `yaml:"signed,omitempty"                     json:"signed,omitempty"`
+	Validations            []string         `yaml:"validations,omitempty"                json:"validations,omitempty"`
+	Extensions             map[string]any   `yaml:"extensions,omitempty"                 json:"extensions,omitempty"`
+	Properties             []*Attribute     `yaml:"properties,omitempty"                 json:"properties,omitempty"`
 
 	ConvertedName       string                    `yaml:"-" json:"-"`
 	ConvertedType       string                    `yaml:"-" json:"-"`
@@ -83,6 +101,26 @@ type Attribute struct {
 	ValidationProviders map[string]*ValidationMap `yaml:"-" json:"-"`
 
 	linkedSpecification Specification
+	includedFrom        string
+
+	// parent is the attribute declaring this
+	// one in its properties, if any.
+	parent *Attribute
+
+	// resolvedEnumValues holds the enum values declared inline
+	// or by the referenced named enum once the set is loaded.
+	// AllowedChoices is then derived from them.
+	resolvedEnumValues []*EnumValue
+}
+
+// ItemConstraints represents the constraints applying
+// to each item of an attribute of type list of string.
+type ItemConstraints struct {
+	AllowedChars        string   `yaml:"allowed_chars,omitempty"         json:"allowed_chars,omitempty"`
+	AllowedCharsMessage string   `yaml:"allowed_chars_message,omitempty" json:"allowed_chars_message,omitempty"`
+	AllowedChoices      []string `yaml:"allowed_choices,omitempty"       json:"allowed_choices,omitempty"`
+	MaxLength           *uint16  `yaml:"max_length,omitempty"            json:"max_length,omitempty"`
+	MinLength           *uint16  `yaml:"min_length,omitempty"            json:"min_length,omitempty"`
 }
 
 // Validate validates the attribute definition.
@@ -90,25 +128,386 @@ func (a *Attribute) Validate() []error {
 
 	var errs []error
 
-	if a.Required && a.DefaultValue == nil && a.ExampleValue == nil {
-		errs = append(errs, fmt.Errorf("%s.spec: '%s' is required but has no default_value or example_value", a.linkedSpecification.Model().RestName, a.Name))
+	if a.Required && a.DefaultValue == nil && a.ExampleValue == nil && a.parent == nil {
+		errs = append(errs, NewLintError(LintRuleAttributeRequiredExample, nil, a, fmt.Errorf("%s: '%s' is required but has no default_value or example_value", a.fileName(), a.qualifiedName())))
 	}
 
 	if a.Description != "" && a.Description[len(a.Description)-1] != '.' && a.linkedSpecification != nil && a.linkedSpecification.Model() != nil {
-		errs = append(errs, fmt.Errorf("%s.spec: description of attribute '%s' must end with a period", a.linkedSpecification.Model().RestName, a.Name))
+		errs = append(errs, NewLintError(LintRuleAttributeDescriptionPeriod, nil, a, fmt.Errorf("%s: description of attribute '%s' must end with a period", a.fileName(), a.qualifiedName())))
 	}
 
-	if a.Type == AttributeTypeEnum && len(a.AllowedChoices) == 0 {
-		errs = append(errs, fmt.Errorf("%s.spec: enum attribute '%s' must define allowed_choices", a.linkedSpecification.Model().RestName, a.Name))
-	}
+	errs = append(errs, validateEnumDeclaration(a.fileName(), "attribute", a.qualifiedName(), a.Type == AttributeTypeEnum, a.declared().AllowedChoices, a.EnumValues, a.EnumReference)...)
 
 	if a.AllowedChars != "" && a.AllowedCharsMessage == "" && a.linkedSpecification != nil && a.linkedSpecification.Model() != nil {
-		errs = append(errs, fmt.Errorf("%s.spec: attribute '%s' must define allowed_chars_message", a.linkedSpecification.Model().RestName, a.Name))
+		errs = append(errs, NewLintError(LintRuleAttributeAllowedCharsMessage, nil, a, fmt.Errorf("%s: attribute '%s' must define allowed_chars_message", a.fileName(), a.qualifiedName())))
 	}
 
+	errs = append(errs, a.validateBounds()...)
+	errs = append(errs, a.validateList()...)
+	errs = append(errs, a.validateMap()...)
+	errs = append(errs, a.validateProperties()...)
+
 	if a.Signed && (a.Autogenerated || a.Transient || a.ReadOnly) {
-		errs = append(errs, fmt.Errorf("%s.spec: attribute '%s' cannot be use for signature if it is autogenerated, transient, read only or not exposed", a.linkedSpecification.Model().RestName, a.Name))
+		errs = append(errs, fmt.Errorf("%s: attribute '%s' cannot be use for signature if it is autogenerated, transient, read only or not exposed", a.fileName(), a.qualifiedName()))
+	}
+
+	return errs
+}
+
+// Choices returns the documented values of the enum, declared inline
+// or by the referenced named enum. It returns nil if the values are
+// only declared by allowed_choices or if the set is not loaded yet.
+func (a *Attribute) Choices() []*EnumValue {
+	return a.resolvedEnumValues
+}
+
+// resolveEnum resolves the enum values of the attribute using the
+// given named enums and sets AllowedChoices. Nothing is resolved if
+// allowed_choices is declared too, so the validation reports it.
+func (a *Attribute) resolveEnum(enums EnumMapping) error {
+
+	if a.resolvedEnumValues != nil || len(a.AllowedChoices) > 0 || (a.EnumValues == nil && a.EnumReference == "") {
+		return nil
+	}
+
+	values, err := resolveEnumValues(enums, a.EnumValues, a.EnumReference)
+	if err != nil {
+		return fmt.Errorf("%s: attribute '%s': %w", a.fileName(), a.qualifiedName(), err)
+	}
+
+	a.resolvedEnumValues = values
+	a.AllowedChoices = enumChoices(values)
+
+	return nil
+}
+
+// declared returns the attribute as declared in the specification,
+// without the allowed choices derived from its enum values.
+func (a *Attribute) declared() *Attribute {
+
+	if a.resolvedEnumValues == nil {
+		return a
+	}
+
+	c := *a
+	c.AllowedChoices = nil
+
+	return &c
+}
+
+// validateBounds validates the length and value constraints.
+// A nil bound is unset, so 0 is a valid bound.
+func (a *Attribute) validateBounds() []error {
+
+	var errs []error
+
+	if a.MinLength != nil && a.MaxLength != nil && *a.MinLength > *a.MaxLength {
+		errs = append(errs, fmt.Errorf("%s: attribute '%s' must have a min_length lower than or equal to its max_length", a.fileName(), a.qualifiedName()))
+	}
+
+	if a.ExclusiveMinValue && a.MinValue == nil {
+		errs = append(errs, fmt.Errorf("%s: attribute '%s' cannot set exclusive_min_value without min_value", a.fileName(), a.qualifiedName()))
+	}
+
+	if a.ExclusiveMaxValue && a.MaxValue == nil {
+		errs = append(errs, fmt.Errorf("%s: attribute '%s' cannot set exclusive_max_value without max_value", a.fileName(), a.qualifiedName()))
+	}
+
+	if a.MinValue != nil && a.MaxValue != nil {
+		if *a.MinValue > *a.MaxValue || (*a.MinValue == *a.MaxValue && (a.ExclusiveMinValue || a.ExclusiveMaxValue)) {
+			errs = append(errs, fmt.Errorf("%s: attribute '%s' has no value between its min_value and its max_value", a.fileName(), a.qualifiedName()))
+		}
+	}
+
+	if a.MultipleOf != nil && *a.MultipleOf <= 0 {
+		errs = append(errs, fmt.Errorf("%s: attribute '%s' must have a multiple_of greater than 0", a.fileName(), a.qualifiedName()))
+	}
+
+	return errs
+}
+
+// validateList validates the list constraints and checks
+// the default and example values of the attribute against them.
+func (a *Attribute) validateList() []error {
+
+	if a.MinItems == nil && a.MaxItems == nil && !a.UniqueItems && a.Items == nil {
+		return nil
+	}
+
+	if a.Type != AttributeTypeList && a.Type != AttributeTypeRefList {
+		return []error{fmt.Errorf("%s: attribute '%s' of type '%s' cannot define list constraints", a.fileName(), a.qualifiedName(), a.Type)}
+	}
+
+	var errs []error
+
+	if a.MinItems != nil && a.MaxItems != nil && *a.MinItems > *a.MaxItems {
+		errs = append(errs, fmt.Errorf("%s: attribute '%s' must have a min_items lower than or equal to its max_items", a.fileName(), a.qualifiedName()))
+	}
+
+	var pattern *regexp.Regexp
+
+	if a.Items != nil {
+
+		if a.Type != AttributeTypeList || a.SubType != string(AttributeTypeString) {
+			return append(errs, fmt.Errorf("%s: attribute '%s' can only define items constraints if it is a list of string", a.fileName(), a.qualifiedName()))
+		}
+
+		if a.Items.MinLength != nil && a.Items.MaxLength != nil && *a.Items.MinLength > *a.Items.MaxLength {
+			errs = append(errs, fmt.Errorf("%s: attribute '%s' must have an items min_length lower than or equal to its items max_length", a.fileName(), a.qualifiedName()))
+		}
+
+		if a.Items.AllowedChars != "" {
+
+			var err error
+			if pattern, err = regexp.Compile(a.Items.AllowedChars); err != nil {
+				errs = append(errs, fmt.Errorf("%s: attribute '%s' has an invalid items allowed_chars: %s", a.fileName(), a.qualifiedName(), err))
+			}
+
+			if a.Items.AllowedCharsMessage == "" && a.linkedSpecification != nil && a.linkedSpecification.Model() != nil {
+				errs = append(errs, NewLintError(LintRuleAttributeAllowedCharsMessage, nil, a, fmt.Errorf("%s: attribute '%s' must define items allowed_chars_message", a.fileName(), a.qualifiedName())))
+			}
+		}
+	}
+
+	if a.DefaultValue != nil {
+		errs = append(errs, a.checkListValue("default_value", a.DefaultValue, pattern)...)
+	}
+
+	if a.ExampleValue != nil {
+		errs = append(errs, a.checkListValue("example_value", a.ExampleValue, pattern)...)
+	}
+
+	return errs
+}
+
+// checkListValue checks the given value of the given field against
+// the list constraints. Values that are not lists are not checked.
+func (a *Attribute) checkListValue(field string, value any, pattern *regexp.Regexp) []error {
+
+	list, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+
+	if a.MinItems != nil && len(list) < int(*a.MinItems) {
+		errs = append(errs, fmt.Errorf("%s: %s of attribute '%s' must have at least %d items", a.fileName(), field, a.qualifiedName(), *a.MinItems))
+	}
+
+	if a.MaxItems != nil && len(list) > int(*a.MaxItems) {
+		errs = append(errs, fmt.Errorf("%s: %s of attribute '%s' must have at most %d items", a.fileName(), field, a.qualifiedName(), *a.MaxItems))
+	}
+
+	seen := map[string]struct{}{}
+
+	for _, item := range list {
+
+		// Items of different types are different, like 1 and "1".
+		key := fmt.Sprintf("%T:%v", item, item)
+		if _, ok := seen[key]; ok && a.UniqueItems {
+			errs = append(errs, fmt.Errorf("%s: %s of attribute '%s' must have unique items: '%v' is duplicated", a.fileName(), field, a.qualifiedName(), item))
+		}
+		seen[key] = struct{}{}
+
+		str, ok := item.(string)
+		if !ok || a.Items == nil {
+			continue
+		}
+
+		length := utf8.RuneCountInString(str)
+
+		switch {
+		case len(a.Items.AllowedChoices) > 0 && !containsString(a.Items.AllowedChoices, str):
+			errs = append(errs, fmt.Errorf("%s: %s of attribute '%s' has item '%s' that is not an allowed choice", a.fileName(), field, a.qualifiedName(), str))
+		case a.Items.MinLength != nil && length < int(*a.Items.MinLength):
+			errs = append(errs, fmt.Errorf("%s: %s of attribute '%s' has item '%s' shorter than %d", a.fileName(), field, a.qualifiedName(), str, *a.Items.MinLength))
+		case a.Items.MaxLength != nil && length > int(*a.Items.MaxLength):
+			errs = append(errs, fmt.Errorf("%s: %s of attribute '%s' has item '%s' longer than %d", a.fileName(), field, a.qualifiedName(), str, *a.Items.MaxLength))
+		case pattern != nil && !pattern.MatchString(str):
+			errs = append(errs, fmt.Errorf("%s: %s of attribute '%s' has item '%s' not matching allowed_chars", a.fileName(), field, a.qualifiedName(), str))
+		}
+	}
+
+	return errs
+}
+
+// validateMap validates the subtype and the key constraints of a
+// map and checks the default and example values of the attribute.
+func (a *Attribute) validateMap() []error {
+
+	if a.Type != AttributeTypeMap && a.Type != AttributeTypeRefMap {
+		if a.KeyAllowedChars != "" || a.KeyAllowedCharsMessage != "" {
+			return []error{fmt.Errorf("%s: attribute '%s' of type '%s' cannot define key constraints", a.fileName(), a.qualifiedName(), a.Type)}
+		}
+		return nil
+	}
+
+	var errs []error
+
+	if a.Type == AttributeTypeMap && !isScalarType(AttributeType(a.SubType)) {
+		errs = append(errs, fmt.Errorf("%s: map attribute '%s' must have a subtype set to string, integer, float, boolean or time", a.fileName(), a.qualifiedName()))
+	}
+
+	var pattern *regexp.Regexp
+
+	if a.KeyAllowedChars != "" {
+
+		var err error
+		if pattern, err = regexp.Compile(a.KeyAllowedChars); err != nil {
+			errs = append(errs, fmt.Errorf("%s: attribute '%s' has an invalid key_allowed_chars: %s", a.fileName(), a.qualifiedName(), err))
+		}
+
+		if a.KeyAllowedCharsMessage == "" && a.linkedSpecification != nil && a.linkedSpecification.Model() != nil {
+			errs = append(errs, NewLintError(LintRuleAttributeAllowedCharsMessage, nil, a, fmt.Errorf("%s: attribute '%s' must define key_allowed_chars_message", a.fileName(), a.qualifiedName())))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if a.DefaultValue != nil {
+		errs = append(errs, a.checkMapValue("default_value", a.DefaultValue, pattern)...)
+	}
+
+	if a.ExampleValue != nil {
+		errs = append(errs, a.checkMapValue("example_value", a.ExampleValue, pattern)...)
+	}
+
+	return errs
+}
+
+// checkMapValue checks the keys of the given value of the given field
+// against the key pattern and, for a map, its values against the subtype.
+func (a *Attribute) checkMapValue(field string, value any, pattern *regexp.Regexp) []error {
+
+	m, ok := massageYAML(value).(map[string]any)
+	if !ok {
+		return []error{fmt.Errorf("%s: %s of attribute '%s' must be a map", a.fileName(), field, a.qualifiedName())}
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+
+	for _, key := range keys {
+
+		if pattern != nil && !pattern.MatchString(key) {
+			errs = append(errs, fmt.Errorf("%s: %s of attribute '%s' has key '%s' not matching key_allowed_chars", a.fileName(), field, a.qualifiedName(), key))
+		}
+
+		if a.Type == AttributeTypeMap && !isScalarValue(AttributeType(a.SubType), m[key]) {
+			errs = append(errs, fmt.Errorf("%s: %s of attribute '%s' has a value for key '%s' that is not of type %s", a.fileName(), field, a.qualifiedName(), key, a.SubType))
+		}
 	}
 
 	return errs
 }
+
+// validateProperties validates the nested properties of an attribute
+// of type object or list of object, and each of them recursively.
+func (a *Attribute) validateProperties() []error {
+
+	if len(a.Properties) == 0 {
+		return nil
+	}
+
+	if a.Type != AttributeTypeObject && (a.Type != AttributeTypeList || a.SubType != string(AttributeTypeObject)) {
+		return []error{fmt.Errorf("%s: attribute '%s' of type '%s' cannot define properties", a.fileName(), a.qualifiedName(), a.Type)}
+	}
+
+	var errs []error
+
+	seen := map[string]struct{}{}
+
+	for _, p := range a.Properties {
+
+		if _, ok := seen[p.Name]; ok {
+			errs = append(errs, fmt.Errorf("%s: attribute '%s' has more than one property named '%s'", a.fileName(), a.qualifiedName(), p.Name))
+		}
+
+		seen[p.Name] = struct{}{}
+
+		errs = append(errs, p.Validate()...)
+	}
+
+	return errs
+}
+
+// linkProperties links the nested properties of the attribute
+// to it and to its specification, recursively.
+func (a *Attribute) linkProperties() {
+
+	for _, p := range a.Properties {
+		p.parent = a
+		p.linkedSpecification = a.linkedSpecification
+		p.linkProperties()
+	}
+}
+
+// qualifiedName returns the name of the attribute prefixed by the
+// names of its parents, to be used in error messages.
+func (a *Attribute) qualifiedName() string {
+
+	if a.parent == nil {
+		return a.Name
+	}
+
+	return a.parent.qualifiedName() + "." + a.Name
+}
+
+// isScalarType returns true if the given type
+// can be used as the subtype of a map.
+func isScalarType(t AttributeType) bool {
+
+	switch t {
+	case AttributeTypeString, AttributeTypeInt, AttributeTypeFloat, AttributeTypeBool, AttributeTypeTime:
+		return true
+	default:
+		return false
+	}
+}
+
+// isScalarValue returns true if the given decoded
+// value is of the given scalar type.
+func isScalarValue(t AttributeType, v any) bool {
+
+	switch v.(type) {
+	case string:
+		return t == AttributeTypeString || t == AttributeTypeTime
+	case time.Time:
+		return t == AttributeTypeTime
+	case int, int64, uint64:
+		return t == AttributeTypeInt || t == AttributeTypeFloat
+	case float64:
+		return t == AttributeTypeFloat
+	case bool:
+		return t == AttributeTypeBool
+	default:
+		return false
+	}
+}
+
+// fileName returns the name of the file declaring the
+// attribute, to be used in error messages.
+func (a *Attribute) fileName() string {
+
+	if a.linkedSpecification == nil {
+		return "unknown"
+	}
+
+	if m := a.linkedSpecification.Model(); m != nil {
+		return m.RestName + ".spec"
+	}
+
+	if s, ok := a.linkedSpecification.(*specification); ok && s.path != "" {
+		return path.Base(s.path)
+	}
+
+	return "abstract"
+}
@@ -12,6 +12,10 @@
 package spec
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -106,7 +110,7 @@ func TestAttribute_Validate(t *testing.T) {
 
 			Convey("Then there should be validation error", func() {
 				So(len(errs), ShouldEqual, 1)
-				So(errs[0].Error(), ShouldEqual, "spec.spec: enum attribute 'name' must define allowed_choices")
+				So(errs[0].Error(), ShouldEqual, "spec.spec: enum attribute 'name' must define allowed_choices, enum_values or enum_reference")
 			})
 		})
 	})
@@ -217,4 +221,352 @@ func TestAttribute_Validate(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given I have attributes with invalid bounds", t, func() {
+
+		zero := 0.0
+		one := 1.0
+		length := uint16(2)
+		shorter := uint16(1)
+
+		tests := map[string]*Attribute{
+			"spec.spec: attribute 'a' must have a min_length lower than or equal to its max_length": {
+				Name:      "a",
+				MinLength: &length,
+				MaxLength: &shorter,
+			},
+			"spec.spec: attribute 'a' cannot set exclusive_min_value without min_value": {
+				Name:              "a",
+				ExclusiveMinValue: true,
+			},
+			"spec.spec: attribute 'a' cannot set exclusive_max_value without max_value": {
+				Name:              "a",
+				ExclusiveMaxValue: true,
+			},
+			"spec.spec: attribute 'a' has no value between its min_value and its max_value": {
+				Name:              "a",
+				MinValue:          &zero,
+				MaxValue:          &zero,
+				ExclusiveMaxValue: true,
+			},
+			"spec.spec: attribute 'a' must have a multiple_of greater than 0": {
+				Name:       "a",
+				MultipleOf: &zero,
+			},
+		}
+
+		conveyInvalidAttributes(tests, AttributeTypeFloat, "")
+
+		Convey("Then zero bounds should be valid", func() {
+			a := &Attribute{
+				Name:       "a",
+				Type:       AttributeTypeFloat,
+				MinValue:   &zero,
+				MaxValue:   &one,
+				MinLength:  new(uint16),
+				MaxLength:  new(uint16),
+				MultipleOf: &one,
+			}
+			So(a.Validate(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given I have list attributes with invalid constraints", t, func() {
+
+		one := uint16(1)
+		two := uint16(2)
+
+		tests := map[string]*Attribute{
+			"spec.spec: attribute 'a' of type 'string' cannot define list constraints": {
+				Type:        AttributeTypeString,
+				UniqueItems: true,
+			},
+			"spec.spec: attribute 'a' must have a min_items lower than or equal to its max_items": {
+				MinItems: &two,
+				MaxItems: &one,
+			},
+			"spec.spec: attribute 'a' can only define items constraints if it is a list of string": {
+				SubType: "integer",
+				Items:   &ItemConstraints{MaxLength: &one},
+			},
+			"spec.spec: attribute 'a' must have an items min_length lower than or equal to its items max_length": {
+				Items: &ItemConstraints{MinLength: &two, MaxLength: &one},
+			},
+			"spec.spec: attribute 'a' has an invalid items allowed_chars: error parsing regexp: missing closing ): `(`": {
+				Items: &ItemConstraints{AllowedChars: "(", AllowedCharsMessage: "oops"},
+			},
+			"spec.spec: attribute 'a' must define items allowed_chars_message": {
+				Items: &ItemConstraints{AllowedChars: "^[a-z]+$"},
+			},
+			"spec.spec: default_value of attribute 'a' must have at least 2 items": {
+				MinItems:     &two,
+				DefaultValue: []any{"a"},
+			},
+			"spec.spec: example_value of attribute 'a' must have at most 1 items": {
+				MaxItems:     &one,
+				ExampleValue: []any{"a", "b"},
+			},
+			"spec.spec: default_value of attribute 'a' must have unique items: 'a' is duplicated": {
+				UniqueItems:  true,
+				DefaultValue: []any{"a", "a"},
+			},
+			"spec.spec: default_value of attribute 'a' has item 'c' that is not an allowed choice": {
+				Items:        &ItemConstraints{AllowedChoices: []string{"a", "b"}},
+				DefaultValue: []any{"a", "c"},
+			},
+			"spec.spec: example_value of attribute 'a' has item 'abc' longer than 2": {
+				Items:        &ItemConstraints{MaxLength: &two},
+				ExampleValue: []any{"abc"},
+			},
+			"spec.spec: example_value of attribute 'a' has item 'A' not matching allowed_chars": {
+				Items:        &ItemConstraints{AllowedChars: "^[a-z]+$", AllowedCharsMessage: "lowercase"},
+				ExampleValue: []any{"A"},
+			},
+		}
+
+		conveyInvalidAttributes(tests, AttributeTypeList, string(AttributeTypeString))
+
+		Convey("Then valid list constraints should be accepted", func() {
+			a := &Attribute{
+				Name:         "a",
+				Type:         AttributeTypeRefList,
+				SubType:      "task",
+				MinItems:     &one,
+				MaxItems:     &one,
+				UniqueItems:  true,
+				DefaultValue: []any{map[any]any{"name": "a"}},
+			}
+			So(a.Validate(), ShouldBeEmpty)
+		})
+
+		Convey("Then unique items of different types should be accepted", func() {
+			a := &Attribute{
+				Name:         "a",
+				Type:         AttributeTypeList,
+				SubType:      string(AttributeTypeObject),
+				UniqueItems:  true,
+				DefaultValue: []any{1, "1"},
+			}
+			So(a.Validate(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given I have map attributes with invalid constraints", t, func() {
+
+		tests := map[string]*Attribute{
+			"spec.spec: attribute 'a' of type 'string' cannot define key constraints": {
+				Type:            AttributeTypeString,
+				KeyAllowedChars: "^[a-z]+$",
+			},
+			"spec.spec: map attribute 'a' must have a subtype set to string, integer, float, boolean or time": {
+				SubType: "task",
+			},
+			"spec.spec: attribute 'a' has an invalid key_allowed_chars: error parsing regexp: missing closing ): `(`": {
+				KeyAllowedChars:        "(",
+				KeyAllowedCharsMessage: "oops",
+			},
+			"spec.spec: attribute 'a' must define key_allowed_chars_message": {
+				KeyAllowedChars: "^[a-z]+$",
+			},
+			"spec.spec: default_value of attribute 'a' must be a map": {
+				DefaultValue: []any{"a"},
+			},
+			"spec.spec: example_value of attribute 'a' has key 'A' not matching key_allowed_chars": {
+				KeyAllowedChars:        "^[a-z]+$",
+				KeyAllowedCharsMessage: "lowercase",
+				ExampleValue:           map[string]any{"A": 1, "b": 2},
+			},
+			"spec.spec: default_value of attribute 'a' has a value for key 'b' that is not of type integer": {
+				DefaultValue: map[string]any{"a": 1, "b": "two"},
+			},
+		}
+
+		conveyInvalidAttributes(tests, AttributeTypeMap, string(AttributeTypeInt))
+
+		Convey("Then valid map constraints should be accepted", func() {
+			a := &Attribute{
+				Name:                   "a",
+				Type:                   AttributeTypeMap,
+				SubType:                string(AttributeTypeFloat),
+				KeyAllowedChars:        "^[a-z]+$",
+				KeyAllowedCharsMessage: "lowercase",
+				DefaultValue:           map[any]any{"a": 1, "b": 2.5},
+			}
+			So(a.Validate(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given I have attributes with invalid properties", t, func() {
+
+		tests := map[string]*Attribute{
+			"spec.spec: attribute 'a' of type 'string' cannot define properties": {
+				Type: AttributeTypeString,
+				Properties: []*Attribute{
+					{Name: "b", Type: AttributeTypeString},
+				},
+			},
+			"spec.spec: attribute 'a' of type 'list' cannot define properties": {
+				Type:    AttributeTypeList,
+				SubType: string(AttributeTypeString),
+				Properties: []*Attribute{
+					{Name: "b", Type: AttributeTypeString},
+				},
+			},
+			"spec.spec: attribute 'a' has more than one property named 'b'": {
+				Properties: []*Attribute{
+					{Name: "b", Type: AttributeTypeString},
+					{Name: "b", Type: AttributeTypeInt},
+				},
+			},
+			"spec.spec: attribute 'a.b.c' has an invalid key_allowed_chars: error parsing regexp: missing closing ): `(`": {
+				Properties: []*Attribute{
+					{
+						Name: "b",
+						Type: AttributeTypeObject,
+						Properties: []*Attribute{
+							{Name: "c", Type: AttributeTypeMap, SubType: "string", KeyAllowedChars: "(", KeyAllowedCharsMessage: "oops"},
+						},
+					},
+				},
+			},
+		}
+
+		conveyInvalidAttributes(tests, AttributeTypeObject, "")
+
+		Convey("Then valid properties should be accepted", func() {
+			a := &Attribute{
+				Name:    "a",
+				Type:    AttributeTypeList,
+				SubType: string(AttributeTypeObject),
+				Properties: []*Attribute{
+					{Name: "b", Description: "The b.", Type: AttributeTypeString, Required: true},
+					{Name: "c", Description: "The c.", Type: AttributeTypeEnum, AllowedChoices: []string{"A", "B"}},
+				},
+			}
+			a.linkProperties()
+			So(a.Validate(), ShouldBeEmpty)
+		})
+	})
+}
+
+// conveyInvalidAttributes checks that validating each of the given
+// attributes returns only the error it is indexed by. The attributes
+// are named 'a', are linked to a specification named 'spec' and get
+// the given type and subtype unless they declare them.
+func conveyInvalidAttributes(tests map[string]*Attribute, typ AttributeType, subType string) {
+
+	linked := &specification{
+		RawModel: &Model{
+			RestName: "spec",
+		},
+	}
+
+	for expected, a := range tests {
+
+		a.Name = "a"
+		a.linkedSpecification = linked
+
+		if a.Type == "" {
+			a.Type = typ
+		}
+
+		if a.SubType == "" {
+			a.SubType = subType
+		}
+
+		a.linkProperties()
+
+		Convey("Then validating it should return '"+expected+"'", func() {
+			errs := a.Validate()
+			So(len(errs), ShouldEqual, 1)
+			So(errs[0].Error(), ShouldEqual, expected)
+		})
+	}
+}
+
+const attributeTestAddress = `  - name: address
+    description: The address of the task.
+    type: object
+    exposed: true
+    properties:
+    - name: street
+      description: The street.
+      type: string
+      required: true
+    - name: points
+      description: The coordinates.
+      type: list
+      subtype: object
+      properties:
+      - name: lat
+        description: The latitude.
+        type: float
+        min_value: -90
+        max_value: 90
+
+`
+
+func TestAttribute_Properties(t *testing.T) {
+
+	task, _ := os.ReadFile("./tests/task.spec")
+
+	Convey("Given I have a spec folder with nested properties", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"task.spec": strings.Replace(string(task), "  - name: description\n", attributeTestAddress+"  - name: description\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			set, err := LoadSpecificationSet(dir, func(n string) string { return strings.ToUpper(n) }, nil, "")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the properties should be loaded", func() {
+				attr := set.Specification("task").Attribute("address", "v1")
+				So(len(attr.Properties), ShouldEqual, 2)
+				So(attr.Properties[0].ConvertedName, ShouldEqual, "STREET")
+				So(attr.Properties[1].Properties[0].ConvertedName, ShouldEqual, "LAT")
+				So(attr.Properties[1].Properties[0].qualifiedName(), ShouldEqual, "address.points.lat")
+			})
+		})
+
+		Convey("When I read the task and write it back", func() {
+
+			s, err := LoadSpecification(filepath.Join(dir, "task.spec"), true)
+			So(err, ShouldBeNil)
+
+			buf := &bytes.Buffer{}
+			So(s.Write(buf), ShouldBeNil)
+
+			Convey("Then the properties should be kept in their declared order", func() {
+				So(buf.String(), ShouldContainSubstring, "    properties:\n    - name: street\n")
+				So(buf.String(), ShouldContainSubstring, "      properties:\n      - name: lat\n        description: The latitude.\n        type: float\n        max_value: 90\n        min_value: -90\n")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with an invalid nested property", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"task.spec": strings.Replace(
+				string(task),
+				"  - name: description\n",
+				strings.Replace(attributeTestAddress, "      required: true\n", "      required: true\n      properties:\n      - name: number\n        description: The number.\n        type: integer\n", 1)+"  - name: description\n",
+				1,
+			),
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "task.spec: attribute 'address.street' of type 'string' cannot define properties")
+			})
+		})
+	})
 }
@@ -11,7 +11,12 @@
 
 package spec
 
-import ini "gopkg.in/ini.v1"
+import (
+	"fmt"
+	"strings"
+
+	ini "gopkg.in/ini.v1"
+)
 
 // Config holds the Specification Config.
 type Config struct {
@@ -24,6 +29,14 @@ type Config struct {
 	URL         string
 	Version     string
 
+	// Dependencies holds the dependencies declared
+	// in the [dependency.<name>] sections.
+	Dependencies []*Dependency
+
+	// Extensions holds the extensions declared
+	// in the [extension.<key>] sections.
+	Extensions ExtensionMapping
+
 	cfg *ini.File
 }
 
@@ -99,6 +112,62 @@ func LoadConfig(path string) (*Config, error) {
 		c.Description = descriptionKey.String()
 	}
 
+	if lintSection, err := cfg.GetSection("lint"); err == nil {
+		for _, key := range lintSection.Keys() {
+			if err := LintSeverity(key.String()).validate(); err != nil {
+				return nil, fmt.Errorf("invalid lint configuration for rule '%s': %w", key.Name(), err)
+			}
+		}
+	}
+
+	for _, section := range cfg.Sections() {
+
+		if !strings.HasPrefix(section.Name(), extensionSectionPrefix) {
+			continue
+		}
+
+		name, def, err := loadExtensionSection(section)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.Extensions == nil {
+			c.Extensions = ExtensionMapping{}
+		}
+		c.Extensions[name] = def
+	}
+
+	if len(c.Extensions) > 0 {
+		if errs := c.Extensions.validate("regolithe.ini"); len(errs) != 0 {
+			return nil, formatValidationErrors(errs)
+		}
+	}
+
+	for _, section := range cfg.Sections() {
+
+		if !strings.HasPrefix(section.Name(), dependencySectionPrefix) {
+			continue
+		}
+
+		dep := &Dependency{
+			Name:   strings.TrimPrefix(section.Name(), dependencySectionPrefix),
+			Path:   section.Key("path").String(),
+			URL:    section.Key("url").String(),
+			Ref:    section.Key("ref").String(),
+			Subdir: section.Key("subdir").String(),
+		}
+
+		if section.HasKey("import") {
+			dep.Import = section.Key("import").Strings(",")
+		}
+
+		if err := dep.validate(); err != nil {
+			return nil, err
+		}
+
+		c.Dependencies = append(c.Dependencies, dep)
+	}
+
 	return c, nil
 }
 
@@ -117,3 +186,18 @@ func (c *Config) Key(section, key string) string {
 
 	return k.String()
 }
+
+// LintSeverity returns the severity configured for the given lint
+// rule ID, or the given default severity if it is not configured.
+func (c *Config) LintSeverity(ruleID string, defaultSeverity LintSeverity) LintSeverity {
+
+	if c.cfg == nil {
+		return defaultSeverity
+	}
+
+	if v := c.Key("lint", ruleID); v != "" {
+		return LintSeverity(v)
+	}
+
+	return defaultSeverity
+}
@@ -0,0 +1,488 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Names of the files and folders used by the dependencies.
+const (
+	dependencySectionPrefix = "dependency."
+	dependencyVendorDir     = "vendor"
+	dependencyLockFile      = "regolithe.lock"
+)
+
+// A Dependency is a specification set another one depends on. It is
+// declared in a [dependency.<name>] section of the regolithe.ini file,
+// using either the path key for a local folder, relative to the
+// specification set, or the url and ref keys for a git repository,
+// with the optional subdir key to read the specifications from a
+// folder of the repository.
+//
+// The abstracts and the mappings of a dependency are available to
+// the dependent specification set, which takes precedence when both
+// define the same name. Like in the dependent specification set, an
+// _extensions.mapping file makes the extension checks strict. The
+// specifications listed in the comma separated import key are added
+// to the dependent specification set, and the other ones are not read.
+// The dependencies of a dependency are not imported.
+type Dependency struct {
+	Name   string
+	Path   string
+	URL    string
+	Ref    string
+	Subdir string
+	Import []string
+}
+
+// validate checks the Dependency is correctly declared.
+func (d *Dependency) validate() error {
+
+	switch {
+	case d.Name == "" || d.Name == "." || strings.Contains(d.Name, "..") || strings.ContainsAny(d.Name, `/\`) || filepath.Clean(d.Name) != d.Name:
+		return fmt.Errorf("invalid dependency '%s': name must be a single folder name", d.Name)
+	case d.Path == "" && d.URL == "":
+		return fmt.Errorf("invalid dependency '%s': either path or url must be set", d.Name)
+	case d.Path != "" && d.URL != "":
+		return fmt.Errorf("invalid dependency '%s': path and url cannot be both set", d.Name)
+	case d.URL != "" && d.Ref == "":
+		return fmt.Errorf("invalid dependency '%s': ref must be set with url", d.Name)
+	case d.Path != "" && (d.Ref != "" || d.Subdir != ""):
+		return fmt.Errorf("invalid dependency '%s': ref and subdir can only be set with url", d.Name)
+	default:
+		return nil
+	}
+}
+
+// source returns the Source providing the dependency of the
+// specification set in the given folder, fetched at the given
+// ref if it is not empty.
+func (d *Dependency) source(dirname string, ref string, opts ...GitSourceOption) Source {
+
+	if d.Path != "" {
+		return NewDirectorySource(d.localPath(dirname))
+	}
+
+	if ref == "" {
+		ref = d.Ref
+	}
+
+	return NewGitSource(d.URL, ref, d.Subdir, opts...)
+}
+
+// localPath returns the path of a local dependency
+// of the specification set in the given folder.
+func (d *Dependency) localPath(dirname string) string {
+
+	if filepath.IsAbs(d.Path) {
+		return d.Path
+	}
+
+	return filepath.Join(dirname, d.Path)
+}
+
+// A LockedDependency holds the resolved version of a Dependency.
+type LockedDependency struct {
+	Path   string `yaml:"path,omitempty"`
+	URL    string `yaml:"url,omitempty"`
+	Ref    string `yaml:"ref,omitempty"`
+	Subdir string `yaml:"subdir,omitempty"`
+	Commit string `yaml:"commit,omitempty"`
+}
+
+// matches returns true if the LockedDependency
+// has been resolved from the given Dependency.
+func (l *LockedDependency) matches(d *Dependency) bool {
+
+	return l.Path == d.Path && l.URL == d.URL && l.Ref == d.Ref && l.Subdir == d.Subdir
+}
+
+// A DependencyLock holds the LockedDependencies indexed by name.
+// It is stored in the regolithe.lock file.
+type DependencyLock map[string]*LockedDependency
+
+// LoadDependencyLock loads a DependencyLock from the given file.
+func LoadDependencyLock(path string) (DependencyLock, error) {
+
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G307
+	defer file.Close() // nolint: errcheck
+
+	lock := DependencyLock{}
+
+	if err = lock.Read(file); err != nil {
+		return nil, err
+	}
+
+	return lock, nil
+}
+
+// Read loads a DependencyLock from the given io.Reader.
+func (l DependencyLock) Read(reader io.Reader) error {
+
+	decoder := yaml.NewDecoder(reader)
+	decoder.SetStrict(true)
+
+	if err := decoder.Decode(&l); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", dependencyLockFile, err)
+	}
+
+	return nil
+}
+
+// Write dumps the DependencyLock into the given io.Writer.
+func (l DependencyLock) Write(writer io.Writer) error {
+
+	repr := yaml.MapSlice{}
+	for _, k := range sortedKeys(l) {
+		repr = append(repr, yaml.MapItem{Key: k, Value: l[k]})
+	}
+
+	data, err := yaml.Marshal(repr)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(data)
+	return err
+}
+
+// VendorOptions holds the options of Vendor.
+type VendorOptions struct {
+
+	// Update ignores the commits recorded in the lock file
+	// and fetches the git dependencies at their ref.
+	Update bool
+
+	// GitOptions are given to the git sources.
+	GitOptions []GitSourceOption
+
+	// Logger is the logger to use. By default, nothing is logged.
+	Logger *slog.Logger
+
+	// Progress is an optional function called with
+	// each progress message of the fetches.
+	Progress func(message string)
+}
+
+// Vendor fetches the dependencies declared in the regolithe.ini file of
+// the specification set in the given folder into its vendor folder, and
+// writes their resolved commits into its regolithe.lock file. Unless
+// opts.Update is set, a git dependency recorded in the lock file with
+// the same url, ref and subdir is fetched at the recorded commit.
+// Once vendored, the git dependencies are loaded from the vendor folder,
+// and the local ones only if their path does not exist anymore.
+func Vendor(ctx context.Context, dirname string, opts VendorOptions) (DependencyLock, error) {
+
+	if opts.Logger == nil {
+		opts.Logger = slog.New(discardHandler{})
+	}
+
+	cfg, err := LoadConfig(path.Join(dirname, "regolithe.ini"))
+	if err != nil {
+		return nil, err
+	}
+
+	lockPath := filepath.Join(dirname, dependencyLockFile)
+
+	previous, err := LoadDependencyLock(lockPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp(dirname, ".vendor-")
+	if err != nil {
+		return nil, err
+	}
+	defer func(f string) { _ = os.RemoveAll(f) }(tmpDir) // nolint: errcheck
+
+	lock := DependencyLock{}
+
+	for _, dep := range cfg.Dependencies {
+
+		var ref string
+		if l, ok := previous[dep.Name]; ok && !opts.Update && l.matches(dep) {
+			ref = l.Commit
+		}
+
+		opts.Logger.Info("Vendoring dependency", "name", dep.Name)
+
+		fetched, err := dep.source(dirname, ref, opts.GitOptions...).Fetch(ctx, FetchOptions{
+			Logger:   opts.Logger,
+			Progress: opts.Progress,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch dependency '%s': %w", dep.Name, err)
+		}
+
+		err = copyFiles(fetched.Path, filepath.Join(tmpDir, dep.Name))
+		fetched.Release()
+		if err != nil {
+			return nil, fmt.Errorf("unable to vendor dependency '%s': %w", dep.Name, err)
+		}
+
+		lock[dep.Name] = &LockedDependency{
+			Path:   dep.Path,
+			URL:    dep.URL,
+			Ref:    dep.Ref,
+			Subdir: dep.Subdir,
+			Commit: fetched.Commit,
+		}
+	}
+
+	vendorDir := filepath.Join(dirname, dependencyVendorDir)
+
+	if err := os.RemoveAll(vendorDir); err != nil {
+		return nil, err
+	}
+
+	if len(lock) == 0 {
+		if err := os.Remove(lockPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		return lock, nil
+	}
+
+	if err := os.Rename(tmpDir, vendorDir); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) // #nosec
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G307
+	defer f.Close() // nolint: errcheck
+
+	if err := lock.Write(f); err != nil {
+		return nil, err
+	}
+
+	return lock, nil
+}
+
+// copyFiles copies the regular files of the src folder and of its
+// subfolders, like the ones holding included files, into the dest
+// folder, which is created. Hidden folders and vendor folders are
+// skipped, as the dependencies of a dependency are not imported.
+func copyFiles(src string, dest string) error {
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dest, 0750); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+
+		if e.IsDir() {
+			if strings.HasPrefix(e.Name(), ".") || e.Name() == dependencyVendorDir {
+				continue
+			}
+			if err := copyFiles(filepath.Join(src, e.Name()), filepath.Join(dest, e.Name())); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(src, e.Name())) // #nosec
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(dest, e.Name()), data, 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dependencyDir returns the folder containing the given dependency of
+// the specification set in the given folder. Local dependencies are read
+// from their path so their changes are seen right away, and only fall back
+// to the vendored copy if the path does not exist. Git dependencies must
+// be vendored.
+func dependencyDir(dirname string, dep *Dependency) (string, error) {
+
+	if dep.Path != "" {
+		if info, err := os.Stat(dep.localPath(dirname)); err == nil && info.IsDir() {
+			return dep.localPath(dirname), nil
+		}
+	}
+
+	vendored := filepath.Join(dirname, dependencyVendorDir, dep.Name)
+	if info, err := os.Stat(vendored); err == nil && info.IsDir() {
+		return vendored, nil
+	}
+
+	if dep.Path == "" {
+		return "", fmt.Errorf("dependency '%s' is not vendored: run 'rego vendor'", dep.Name)
+	}
+
+	return dep.localPath(dirname), nil
+}
+
+// loadDependencies imports the abstracts, the mappings and
+// the specifications of the dependencies of the set.
+func (s *specificationSet) loadDependencies(dirname string) error {
+
+	for _, dep := range s.configuration.Dependencies {
+
+		depDir, err := dependencyDir(dirname, dep)
+		if err != nil {
+			return err
+		}
+
+		entries, err := os.ReadDir(depDir)
+		if err != nil {
+			return fmt.Errorf("unable to read dependency '%s': %w", dep.Name, err)
+		}
+
+		imports := map[string]struct{}{}
+		for _, name := range dep.Import {
+			imports[name] = struct{}{}
+		}
+
+		for _, e := range entries {
+
+			if e.IsDir() {
+				continue
+			}
+
+			p := path.Join(depDir, e.Name())
+
+			switch e.Name() {
+
+			case "_type.mapping":
+
+				tm, err := LoadTypeMapping(p)
+				if err != nil {
+					return fmt.Errorf("dependency '%s': %w", dep.Name, err)
+				}
+				s.typeMap = mergeMissing(s.typeMap, tm)
+
+			case "_validation.mapping":
+
+				vm, err := LoadValidationMapping(p)
+				if err != nil {
+					return fmt.Errorf("dependency '%s': %w", dep.Name, err)
+				}
+				s.validationsMap = mergeMissing(s.validationsMap, vm)
+
+			case "_parameter.mapping":
+
+				pm, err := LoadGlobalParameters(p)
+				if err != nil {
+					return fmt.Errorf("dependency '%s': %w", dep.Name, err)
+				}
+				s.parametersMap = mergeMissing(s.parametersMap, pm)
+
+			case "_extensions.mapping":
+
+				em, err := LoadExtensionMapping(p)
+				if err != nil {
+					return fmt.Errorf("dependency '%s': %w", dep.Name, err)
+				}
+				s.extensionsMap = mergeMissing(s.extensionsMap, em)
+				s.strictExtensions = true
+
+			case "_enums.mapping":
+
+				em, err := LoadEnumMapping(p)
+				if err != nil {
+					return fmt.Errorf("dependency '%s': %w", dep.Name, err)
+				}
+				s.enumsMap = mergeMissing(s.enumsMap, em)
+
+			default:
+
+				switch path.Ext(e.Name()) {
+
+				case ".abs":
+
+					name, abs, err := loadSpecificationFile(depDir, e.Name(), nil)
+					if err != nil {
+						return fmt.Errorf("dependency '%s': %w", dep.Name, err)
+					}
+
+					if _, ok := s.abstracts[name]; !ok {
+						s.abstracts[name] = abs
+					}
+
+				case ".spec":
+
+					name := specificationName(e.Name())
+					if _, ok := imports[name]; !ok {
+						continue
+					}
+
+					if _, ok := s.specs[name]; ok {
+						return fmt.Errorf("dependency '%s': imported spec '%s' is already defined", dep.Name, name)
+					}
+
+					_, spec, err := loadSpecificationFile(depDir, e.Name(), nil)
+					if err != nil {
+						return fmt.Errorf("dependency '%s': %w", dep.Name, err)
+					}
+
+					s.specs[name] = spec
+					delete(imports, name)
+				}
+			}
+		}
+
+		if len(imports) > 0 {
+			return fmt.Errorf("dependency '%s': unable to find imported spec '%s'", dep.Name, sortedKeys(imports)[0])
+		}
+	}
+
+	return nil
+}
+
+// mergeMissing adds the entries of src that are not in dst to dst.
+// It returns dst, which is created if nil.
+func mergeMissing[M ~map[string]V, V any](dst M, src M) M {
+
+	if dst == nil {
+		dst = M{}
+	}
+
+	for k, v := range src {
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,399 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const dependencyTestRoot = `# Model
+model:
+  rest_name: root
+  resource_name: root
+  entity_name: Root
+  package: product
+  group: core
+  description: Root object of the API.
+  get:
+    description: Retrieve the root object.
+  root: true
+
+# Relations
+relations:
+- rest_name: project
+  get:
+    description: Retrieves the projects.
+
+- rest_name: user
+  get:
+    description: Retrieves the users.
+`
+
+const dependencyTestProject = `# Model
+model:
+  rest_name: project
+  resource_name: projects
+  entity_name: Project
+  package: product
+  group: core
+  description: Represents a project.
+  extends:
+  - '@base'
+
+# Attributes
+attributes:
+  v1:
+  - name: labels
+    description: The labels of the project.
+    type: external
+    subtype: string_map
+    exposed: true
+    stored: true
+`
+
+// makeDependencyTestFolders creates a platform specification set, copied
+// from the test specifications, and a product specification set depending
+// on it with the given dependency section. The sections can use the
+// PLATFORM placeholder. It returns the path of the product folder.
+func makeDependencyTestFolders(t *testing.T, section string) string {
+
+	platform := copyTestFolder(t, nil)
+	product := t.TempDir()
+
+	files := map[string]string{
+		"regolithe.ini": "[regolithe]\nproduct_name = Product\n\n[transformer]\nname = product\nversion = 1.0\n\n" +
+			string(bytes.ReplaceAll([]byte(section), []byte("PLATFORM"), []byte(platform))),
+		"root.spec":    dependencyTestRoot,
+		"project.spec": dependencyTestProject,
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(product, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return product
+}
+
+func TestDependency_LoadConfig(t *testing.T) {
+
+	write := func(section string) string {
+		p := filepath.Join(t.TempDir(), "regolithe.ini")
+		if err := os.WriteFile(p, []byte("[regolithe]\nproduct_name = P\n\n[transformer]\nname = p\nversion = 1.0\n\n"+section), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	Convey("Given I load a regolithe.ini declaring dependencies", t, func() {
+
+		cfg, err := LoadConfig(write("[dependency.platform]\npath = ../platform\nimport = user, task\n\n[dependency.common]\nurl = git@example.com:org/common.git\nref = v1.0.0\nsubdir = specs\n"))
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the dependencies should be correct", func() {
+			So(cfg.Dependencies, ShouldResemble, []*Dependency{
+				{Name: "platform", Path: "../platform", Import: []string{"user", "task"}},
+				{Name: "common", URL: "git@example.com:org/common.git", Ref: "v1.0.0", Subdir: "specs"},
+			})
+		})
+	})
+
+	Convey("Given I load a regolithe.ini declaring invalid dependencies", t, func() {
+
+		for section, expected := range map[string]string{
+			"[dependency.a]\n":                         "invalid dependency 'a': either path or url must be set",
+			"[dependency.a]\npath = a\nurl = b\n":      "invalid dependency 'a': path and url cannot be both set",
+			"[dependency.a]\nurl = b\n":                "invalid dependency 'a': ref must be set with url",
+			"[dependency.a]\npath = a\nref = master\n": "invalid dependency 'a': ref and subdir can only be set with url",
+			"[dependency.../../x]\npath = a\n":         "invalid dependency '../../x': name must be a single folder name",
+			"[dependency.a/b]\npath = a\n":             "invalid dependency 'a/b': name must be a single folder name",
+			"[dependency.a\\b]\npath = a\n":            "invalid dependency 'a\\b': name must be a single folder name",
+			"[dependency...]\npath = a\n":              "invalid dependency '..': name must be a single folder name",
+			"[dependency.]\npath = a\n":                "invalid dependency '': name must be a single folder name",
+		} {
+			_, err := LoadConfig(write(section))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, expected)
+		}
+	})
+}
+
+func TestDependency_LoadSpecificationSet(t *testing.T) {
+
+	Convey("Given I have a specification set depending on a local one", t, func() {
+
+		dir := makeDependencyTestFolders(t, "[dependency.platform]\npath = PLATFORM\nimport = user\n")
+
+		Convey("When I load it", func() {
+
+			set, err := LoadSpecificationSet(dir, nil, nil, "test")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the imported specs should be part of the set", func() {
+				So(set.Len(), ShouldEqual, 3)
+				So(set.Specification("user"), ShouldNotBeNil)
+				So(set.Specification("task"), ShouldBeNil)
+			})
+
+			Convey("Then the abstracts of the dependency should be applied", func() {
+				So(set.Specification("project").Attribute("ID", "v1"), ShouldNotBeNil)
+			})
+
+			Convey("Then the mappings of the dependency should be used", func() {
+				So(set.Specification("project").Attribute("labels", "v1").ConvertedType, ShouldEqual, "map[string]string")
+				So(set.Specification("user").Model().ValidationProviders, ShouldNotBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given I have a specification set depending on a local one with a broken spec that is not imported", t, func() {
+
+		dir := makeDependencyTestFolders(t, "[dependency.platform]\npath = PLATFORM\nimport = user\n")
+		cfg, _ := LoadConfig(filepath.Join(dir, "regolithe.ini"))
+		So(os.WriteFile(filepath.Join(cfg.Dependencies[0].Path, "broken.spec"), []byte("not: [a spec"), 0600), ShouldBeNil)
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given I have a specification set depending on a local one declaring extensions", t, func() {
+
+		dir := makeDependencyTestFolders(t, "[dependency.platform]\npath = PLATFORM\nimport = user\n")
+		cfg, _ := LoadConfig(filepath.Join(dir, "regolithe.ini"))
+		So(os.WriteFile(filepath.Join(cfg.Dependencies[0].Path, "_extensions.mapping"), []byte(testExtensionMapping), 0600), ShouldBeNil)
+
+		project := strings.Replace(dependencyTestProject, "  extends:\n", "  extensions:\n    orderKey: name\n  extends:\n", 1)
+		So(os.WriteFile(filepath.Join(dir, "project.spec"), []byte(project), 0600), ShouldBeNil)
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then the extensions should be validated strictly", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "project.spec: model: unknown extension 'orderKey'")
+			})
+		})
+	})
+
+	Convey("Given I have a specification set importing a missing spec", t, func() {
+
+		dir := makeDependencyTestFolders(t, "[dependency.platform]\npath = PLATFORM\nimport = user, nope\n")
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "dependency 'platform': unable to find imported spec 'nope'")
+			})
+		})
+	})
+
+	Convey("Given I have a specification set importing a spec it defines", t, func() {
+
+		dir := makeDependencyTestFolders(t, "[dependency.platform]\npath = PLATFORM\nimport = user, root\n")
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "dependency 'platform': imported spec 'root' is already defined")
+			})
+		})
+	})
+
+	Convey("Given I have a specification set depending on a git repository that is not vendored", t, func() {
+
+		dir := makeDependencyTestFolders(t, "[dependency.platform]\nurl = PLATFORM\nref = master\nimport = user\n")
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "dependency 'platform' is not vendored: run 'rego vendor'")
+			})
+		})
+	})
+}
+
+func TestDependency_Vendor(t *testing.T) {
+
+	Convey("Given I have a specification set depending on a local one", t, func() {
+
+		dir := makeDependencyTestFolders(t, "[dependency.platform]\npath = PLATFORM\nimport = user\n")
+		cfg, _ := LoadConfig(filepath.Join(dir, "regolithe.ini"))
+		platform := cfg.Dependencies[0].Path
+
+		Convey("When I vendor it", func() {
+
+			lock, err := Vendor(context.Background(), dir, VendorOptions{})
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the lock should be correct", func() {
+				So(lock, ShouldResemble, DependencyLock{"platform": {Path: platform}})
+				written, err := LoadDependencyLock(filepath.Join(dir, "regolithe.lock"))
+				So(err, ShouldBeNil)
+				So(written, ShouldResemble, lock)
+			})
+
+			Convey("Then the set should be loaded from the vendor folder if the dependency is missing", func() {
+				So(os.RemoveAll(platform), ShouldBeNil)
+				set, err := LoadSpecificationSet(dir, nil, nil, "")
+				So(err, ShouldBeNil)
+				So(set.Specification("user"), ShouldNotBeNil)
+			})
+
+			Convey("Then the set should see the changes of the dependency", func() {
+				user, err := os.ReadFile(filepath.Join(platform, "user.spec"))
+				So(err, ShouldBeNil)
+				user = bytes.Replace(user, []byte("  description: "), []byte("  description: Edited. "), 1)
+				So(os.WriteFile(filepath.Join(platform, "user.spec"), user, 0600), ShouldBeNil)
+
+				set, err := LoadSpecificationSet(dir, nil, nil, "")
+				So(err, ShouldBeNil)
+				So(set.Specification("user").Model().Description, ShouldStartWith, "Edited. ")
+			})
+		})
+	})
+
+	Convey("Given I have a specification set depending on a local one with includes in a subfolder", t, func() {
+
+		dir := makeDependencyTestFolders(t, "[dependency.platform]\npath = PLATFORM\nimport = user\n")
+		cfg, _ := LoadConfig(filepath.Join(dir, "regolithe.ini"))
+		platform := cfg.Dependencies[0].Path
+
+		user, err := os.ReadFile(filepath.Join(platform, "user.spec"))
+		So(err, ShouldBeNil)
+		user = bytes.Replace(user, []byte("  v1:\n"), []byte("  v1:\n  - include: fragments/user.yaml\n"), 1)
+		So(os.WriteFile(filepath.Join(platform, "user.spec"), user, 0600), ShouldBeNil)
+		So(os.Mkdir(filepath.Join(platform, "fragments"), 0750), ShouldBeNil)
+		So(os.WriteFile(filepath.Join(platform, "fragments", "user.yaml"), []byte(includeTestAttributes), 0600), ShouldBeNil)
+
+		Convey("When I vendor it", func() {
+
+			_, err := Vendor(context.Background(), dir, VendorOptions{})
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the included files should be vendored", func() {
+				So(os.RemoveAll(platform), ShouldBeNil)
+				set, err := LoadSpecificationSet(dir, nil, nil, "")
+				So(err, ShouldBeNil)
+				So(set.Specification("user").Attribute("priority", "v1"), ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given I have a specification set depending on a git repository", t, func() {
+
+		repo, first := makeTestRepository(t)
+		dir := makeDependencyTestFolders(t, "[dependency.platform]\nurl = "+repo+"\nref = master\nimport = user\n")
+
+		Convey("When I vendor it", func() {
+
+			lock, err := Vendor(context.Background(), dir, VendorOptions{})
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the head of the branch should be locked", func() {
+				So(lock["platform"].Commit, ShouldNotEqual, first.String())
+				So(len(lock["platform"].Commit), ShouldEqual, 40)
+			})
+
+			Convey("Then the set should be loadable", func() {
+				set, err := LoadSpecificationSet(dir, nil, nil, "")
+				So(err, ShouldBeNil)
+				So(set.Specification("user"), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When I vendor it with a lock file", func() {
+
+			locked := DependencyLock{"platform": {URL: repo, Ref: "master", Commit: first.String()}}
+			buf := &bytes.Buffer{}
+			So(locked.Write(buf), ShouldBeNil)
+			So(os.WriteFile(filepath.Join(dir, "regolithe.lock"), buf.Bytes(), 0600), ShouldBeNil)
+
+			lock, err := Vendor(context.Background(), dir, VendorOptions{})
+
+			Convey("Then the locked commit should be used", func() {
+				So(err, ShouldBeNil)
+				So(lock["platform"].Commit, ShouldEqual, first.String())
+			})
+
+			Convey("When I vendor it again with update", func() {
+
+				lock, err := Vendor(context.Background(), dir, VendorOptions{Update: true})
+
+				Convey("Then the head of the branch should be locked", func() {
+					So(err, ShouldBeNil)
+					So(lock["platform"].Commit, ShouldNotEqual, first.String())
+				})
+			})
+		})
+	})
+
+	Convey("Given I have a specification set without dependencies", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"regolithe.lock": "old:\n  path: ../old\n",
+		})
+		So(os.Mkdir(filepath.Join(dir, "vendor"), 0750), ShouldBeNil)
+
+		Convey("When I vendor it", func() {
+
+			lock, err := Vendor(context.Background(), dir, VendorOptions{})
+
+			Convey("Then the vendor folder and the lock file should be removed", func() {
+				So(err, ShouldBeNil)
+				So(lock, ShouldBeEmpty)
+				_, err := os.Stat(filepath.Join(dir, "vendor"))
+				So(os.IsNotExist(err), ShouldBeTrue)
+				_, err = os.Stat(filepath.Join(dir, "regolithe.lock"))
+				So(os.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+	})
+}
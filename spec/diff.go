@@ -0,0 +1,689 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeLevel represents the impact of a Change on the clients.
+type ChangeLevel string
+
+// Various values for ChangeLevel.
+const (
+	ChangeLevelBreaking      ChangeLevel = "breaking"
+	ChangeLevelNonBreaking   ChangeLevel = "non-breaking"
+	ChangeLevelInformational ChangeLevel = "informational"
+)
+
+// A Change represents a single difference between
+// two SpecificationSets.
+type Change struct {
+	Level   ChangeLevel `json:"level"`
+	Path    string      `json:"path"`
+	Message string      `json:"message"`
+}
+
+// String returns the string representation of the Change.
+func (c *Change) String() string {
+	return fmt.Sprintf("%s: %s: %s", c.Level, c.Path, c.Message)
+}
+
+// Changes is a list of *Change.
+type Changes []*Change
+
+// Breaking returns true if at least one of the changes is breaking.
+func (c Changes) Breaking() bool {
+
+	for _, change := range c {
+		if change.Level == ChangeLevelBreaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Level returns the changes with the given level.
+func (c Changes) Level(level ChangeLevel) Changes {
+
+	var out Changes
+
+	for _, change := range c {
+		if change.Level == level {
+			out = append(out, change)
+		}
+	}
+
+	return out
+}
+
+// DiffSpecificationSets compares the old SpecificationSet with the new
+// one and returns the list of classified changes, sorted by path.
+func DiffSpecificationSets(oldSet SpecificationSet, newSet SpecificationSet) Changes {
+
+	d := &differ{}
+
+	for _, oldSpec := range oldSet.Specifications() {
+
+		restName := oldSpec.Model().RestName
+		newSpec := newSet.Specification(restName)
+
+		if newSpec == nil {
+			d.add(ChangeLevelBreaking, restName+".spec", "specification has been removed")
+			continue
+		}
+
+		d.diffSpecifications(oldSpec, newSpec)
+	}
+
+	for _, newSpec := range newSet.Specifications() {
+
+		restName := newSpec.Model().RestName
+
+		if oldSet.Specification(restName) == nil {
+			d.add(ChangeLevelNonBreaking, restName+".spec", "specification has been added")
+		}
+	}
+
+	sort.SliceStable(d.changes, func(i int, j int) bool {
+		return strings.Compare(d.changes[i].Path, d.changes[j].Path) == -1
+	})
+
+	return d.changes
+}
+
+type differ struct {
+	changes Changes
+}
+
+func (d *differ) add(level ChangeLevel, path string, format string, args ...any) {
+
+	d.changes = append(d.changes, &Change{
+		Level:   level,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) diffSpecifications(oldSpec Specification, newSpec Specification) {
+
+	path := oldSpec.Model().RestName + ".spec"
+
+	d.diffModels(path, oldSpec.Model(), newSpec.Model())
+
+	oldAttrs := oldSpec.Attributes(oldSpec.LatestAttributesVersion())
+	newAttrs := newSpec.Attributes(newSpec.LatestAttributesVersion())
+
+	newAttrsMap := make(map[string]*Attribute, len(newAttrs))
+	for _, attr := range newAttrs {
+		newAttrsMap[attr.Name] = attr
+	}
+
+	oldAttrsMap := make(map[string]*Attribute, len(oldAttrs))
+	for _, attr := range oldAttrs {
+
+		oldAttrsMap[attr.Name] = attr
+		attrPath := fmt.Sprintf("%s: attribute '%s'", path, attr.Name)
+
+		newAttr, ok := newAttrsMap[attr.Name]
+		if !ok {
+			if attr.Exposed {
+				d.add(ChangeLevelBreaking, attrPath, "attribute has been removed")
+			} else {
+				d.add(ChangeLevelInformational, attrPath, "unexposed attribute has been removed")
+			}
+			continue
+		}
+
+		d.diffAttributes(attrPath, attr, newAttr)
+	}
+
+	for _, attr := range newAttrs {
+
+		if _, ok := oldAttrsMap[attr.Name]; ok {
+			continue
+		}
+
+		attrPath := fmt.Sprintf("%s: attribute '%s'", path, attr.Name)
+
+		if attr.Required && attr.DefaultValue == nil {
+			d.add(ChangeLevelBreaking, attrPath, "required attribute has been added")
+		} else {
+			d.add(ChangeLevelNonBreaking, attrPath, "attribute has been added")
+		}
+	}
+
+	for _, rel := range oldSpec.Relations() {
+
+		relPath := fmt.Sprintf("%s: relation '%s'", path, rel.RestName)
+
+		newRel := newSpec.Relation(rel.RestName)
+		if newRel == nil {
+			d.add(ChangeLevelBreaking, relPath, "relation has been removed")
+			continue
+		}
+
+		d.diffRelationActions(relPath, "get", rel.Get, newRel.Get)
+		d.diffRelationActions(relPath, "create", rel.Create, newRel.Create)
+		d.diffRelationActions(relPath, "update", rel.Update, newRel.Update)
+		d.diffRelationActions(relPath, "delete", rel.Delete, newRel.Delete)
+	}
+
+	for _, rel := range newSpec.Relations() {
+		if oldSpec.Relation(rel.RestName) == nil {
+			d.add(ChangeLevelNonBreaking, fmt.Sprintf("%s: relation '%s'", path, rel.RestName), "relation has been added")
+		}
+	}
+}
+
+func (d *differ) diffModels(path string, oldModel *Model, newModel *Model) {
+
+	if oldModel.ResourceName != newModel.ResourceName {
+		d.add(ChangeLevelBreaking, path, "resource_name changed from '%s' to '%s'", oldModel.ResourceName, newModel.ResourceName)
+	}
+
+	if oldModel.EntityName != newModel.EntityName {
+		d.add(ChangeLevelBreaking, path, "entity_name changed from '%s' to '%s'", oldModel.EntityName, newModel.EntityName)
+	}
+
+	if oldModel.EntityNamePlural != newModel.EntityNamePlural {
+		d.add(ChangeLevelBreaking, path, "entity_name_plural changed from '%s' to '%s'", oldModel.EntityNamePlural, newModel.EntityNamePlural)
+	}
+
+	if !oldModel.Private && newModel.Private {
+		d.add(ChangeLevelBreaking, path, "specification became private")
+	}
+
+	if oldModel.Private && !newModel.Private {
+		d.add(ChangeLevelNonBreaking, path, "specification became public")
+	}
+
+	if oldModel.Package != newModel.Package {
+		d.add(ChangeLevelInformational, path, "package changed from '%s' to '%s'", oldModel.Package, newModel.Package)
+	}
+
+	if oldModel.Group != newModel.Group {
+		d.add(ChangeLevelInformational, path, "group changed from '%s' to '%s'", oldModel.Group, newModel.Group)
+	}
+
+	if oldModel.Description != newModel.Description {
+		d.add(ChangeLevelInformational, path, "description changed")
+	}
+
+	if oldModel.Documentation != newModel.Documentation {
+		d.add(ChangeLevelInformational, path, "documentation changed")
+	}
+
+	for _, alias := range oldModel.Aliases {
+		if !containsString(newModel.Aliases, alias) {
+			d.add(ChangeLevelBreaking, path, "alias '%s' has been removed", alias)
+		}
+	}
+
+	for _, alias := range newModel.Aliases {
+		if !containsString(oldModel.Aliases, alias) {
+			d.add(ChangeLevelNonBreaking, path, "alias '%s' has been added", alias)
+		}
+	}
+
+	d.diffRelationActions(path, "get", oldModel.Get, newModel.Get)
+	d.diffRelationActions(path, "update", oldModel.Update, newModel.Update)
+	d.diffRelationActions(path, "delete", oldModel.Delete, newModel.Delete)
+}
+
+func (d *differ) diffAttributes(path string, oldAttr *Attribute, newAttr *Attribute) {
+
+	if oldAttr.Type != newAttr.Type {
+		d.add(ChangeLevelBreaking, path, "type changed from '%s' to '%s'", oldAttr.Type, newAttr.Type)
+	} else if oldAttr.SubType != newAttr.SubType {
+		d.add(ChangeLevelBreaking, path, "subtype changed from '%s' to '%s'", oldAttr.SubType, newAttr.SubType)
+	}
+
+	if oldAttr.ExposedName != newAttr.ExposedName {
+		d.add(ChangeLevelBreaking, path, "exposed_name changed from '%s' to '%s'", oldAttr.ExposedName, newAttr.ExposedName)
+	}
+
+	if oldAttr.Exposed && !newAttr.Exposed {
+		d.add(ChangeLevelBreaking, path, "attribute is not exposed anymore")
+	}
+
+	if !oldAttr.Exposed && newAttr.Exposed {
+		d.add(ChangeLevelNonBreaking, path, "attribute is now exposed")
+	}
+
+	if !oldAttr.Required && newAttr.Required {
+		d.add(ChangeLevelBreaking, path, "attribute became required")
+	}
+
+	if oldAttr.Required && !newAttr.Required {
+		d.add(ChangeLevelNonBreaking, path, "attribute is not required anymore")
+	}
+
+	if !oldAttr.ReadOnly && newAttr.ReadOnly {
+		d.add(ChangeLevelBreaking, path, "attribute became read only")
+	}
+
+	if oldAttr.ReadOnly && !newAttr.ReadOnly {
+		d.add(ChangeLevelNonBreaking, path, "attribute is not read only anymore")
+	}
+
+	if !oldAttr.CreationOnly && newAttr.CreationOnly {
+		d.add(ChangeLevelBreaking, path, "attribute became creation only")
+	}
+
+	if oldAttr.CreationOnly && !newAttr.CreationOnly {
+		d.add(ChangeLevelNonBreaking, path, "attribute is not creation only anymore")
+	}
+
+	d.diffChoices(path, oldAttr.AllowedChoices, newAttr.AllowedChoices)
+	d.diffDeprecatedChoices(path, oldAttr.AllowedChoices, oldAttr.Choices(), newAttr.Choices())
+
+	d.diffAllowedChars(path, oldAttr.AllowedChars, newAttr.AllowedChars)
+
+	d.diffUpperBound(path, "max_length", lengthBound(oldAttr.MaxLength), lengthBound(newAttr.MaxLength))
+	d.diffLengthLowerBound(path, "min_length", oldAttr.MinLength, newAttr.MinLength)
+	d.diffUpperBound(path, "max_value", oldAttr.MaxValue, newAttr.MaxValue)
+	d.diffLowerBound(path, "min_value", oldAttr.MinValue, newAttr.MinValue)
+	d.diffExclusiveBound(path, "max_value", oldAttr.ExclusiveMaxValue, newAttr.ExclusiveMaxValue)
+	d.diffExclusiveBound(path, "min_value", oldAttr.ExclusiveMinValue, newAttr.ExclusiveMinValue)
+	d.diffMultipleOf(path, oldAttr.MultipleOf, newAttr.MultipleOf)
+	d.diffUpperBound(path, "max_items", lengthBound(oldAttr.MaxItems), lengthBound(newAttr.MaxItems))
+	d.diffLengthLowerBound(path, "min_items", oldAttr.MinItems, newAttr.MinItems)
+
+	if !oldAttr.UniqueItems && newAttr.UniqueItems {
+		d.add(ChangeLevelBreaking, path, "items must now be unique")
+	}
+
+	if oldAttr.UniqueItems && !newAttr.UniqueItems {
+		d.add(ChangeLevelNonBreaking, path, "items do not need to be unique anymore")
+	}
+
+	d.diffItems(path+" items", oldAttr.Items, newAttr.Items)
+	d.diffAllowedChars(path+" keys", oldAttr.KeyAllowedChars, newAttr.KeyAllowedChars)
+	d.diffProperties(path, oldAttr.Properties, newAttr.Properties)
+
+	if !reflect.DeepEqual(oldAttr.DefaultValue, newAttr.DefaultValue) {
+		d.add(ChangeLevelNonBreaking, path, "default_value changed from '%v' to '%v'", oldAttr.DefaultValue, newAttr.DefaultValue)
+	}
+
+	if !oldAttr.Deprecated && newAttr.Deprecated {
+		d.add(ChangeLevelInformational, path, "attribute has been deprecated")
+	}
+
+	if oldAttr.Description != newAttr.Description {
+		d.add(ChangeLevelInformational, path, "description changed")
+	}
+}
+
+func (d *differ) diffChoices(path string, oldChoices []string, newChoices []string) {
+
+	for _, choice := range oldChoices {
+		if !containsString(newChoices, choice) {
+			d.add(ChangeLevelBreaking, path, "allowed choice '%s' has been removed", choice)
+		}
+	}
+
+	// An empty list means anything is allowed.
+	if len(oldChoices) == 0 {
+		if len(newChoices) > 0 {
+			d.add(ChangeLevelBreaking, path, "allowed_choices have been introduced")
+		}
+		return
+	}
+
+	for _, choice := range newChoices {
+		if !containsString(oldChoices, choice) {
+			d.add(ChangeLevelNonBreaking, path, "allowed choice '%s' has been added", choice)
+		}
+	}
+}
+
+// diffDeprecatedChoices reports the allowed choices that have been
+// deprecated. The old choices without enum values are not deprecated.
+func (d *differ) diffDeprecatedChoices(path string, oldChoices []string, oldValues []*EnumValue, newValues []*EnumValue) {
+
+	deprecated := make(map[string]bool, len(oldChoices))
+	for _, choice := range oldChoices {
+		deprecated[choice] = false
+	}
+
+	for _, v := range oldValues {
+		deprecated[v.Value] = v.Deprecated
+	}
+
+	for _, v := range newValues {
+		if was, ok := deprecated[v.Value]; ok && !was && v.Deprecated {
+			d.add(ChangeLevelInformational, path, "allowed choice '%s' has been deprecated", v.Value)
+		}
+	}
+}
+
+// diffAllowedChars reports changes of allowed_chars.
+func (d *differ) diffAllowedChars(path string, oldChars string, newChars string) {
+
+	switch {
+	case oldChars == newChars:
+	case newChars == "":
+		d.add(ChangeLevelNonBreaking, path, "allowed_chars has been removed")
+	default:
+		d.add(ChangeLevelBreaking, path, "allowed_chars changed from '%s' to '%s'", oldChars, newChars)
+	}
+}
+
+// diffItems reports changes of the constraints applying to
+// each item of a list. Nil constraints allow anything.
+func (d *differ) diffItems(path string, oldItems *ItemConstraints, newItems *ItemConstraints) {
+
+	if oldItems == nil {
+		oldItems = &ItemConstraints{}
+	}
+
+	if newItems == nil {
+		newItems = &ItemConstraints{}
+	}
+
+	d.diffChoices(path, oldItems.AllowedChoices, newItems.AllowedChoices)
+	d.diffAllowedChars(path, oldItems.AllowedChars, newItems.AllowedChars)
+	d.diffUpperBound(path, "max_length", lengthBound(oldItems.MaxLength), lengthBound(newItems.MaxLength))
+	d.diffLengthLowerBound(path, "min_length", oldItems.MinLength, newItems.MinLength)
+}
+
+// diffProperties reports changes of the nested properties
+// of an attribute, and of each of them recursively.
+func (d *differ) diffProperties(path string, oldProps []*Attribute, newProps []*Attribute) {
+
+	newPropsMap := make(map[string]*Attribute, len(newProps))
+	for _, p := range newProps {
+		newPropsMap[p.Name] = p
+	}
+
+	oldPropsMap := make(map[string]*Attribute, len(oldProps))
+	for _, p := range oldProps {
+
+		oldPropsMap[p.Name] = p
+		propPath := fmt.Sprintf("%s property '%s'", path, p.Name)
+
+		newProp, ok := newPropsMap[p.Name]
+		if !ok {
+			d.add(ChangeLevelBreaking, propPath, "property has been removed")
+			continue
+		}
+
+		d.diffAttributes(propPath, p, newProp)
+	}
+
+	for _, p := range newProps {
+
+		if _, ok := oldPropsMap[p.Name]; ok {
+			continue
+		}
+
+		propPath := fmt.Sprintf("%s property '%s'", path, p.Name)
+
+		if p.Required && p.DefaultValue == nil {
+			d.add(ChangeLevelBreaking, propPath, "required property has been added")
+		} else {
+			d.add(ChangeLevelNonBreaking, propPath, "property has been added")
+		}
+	}
+}
+
+// diffUpperBound reports changes of a bound where nil means unset
+// and lowering the value restricts what is accepted.
+func (d *differ) diffUpperBound(path string, name string, oldValue *float64, newValue *float64) {
+
+	switch {
+	case equalBounds(oldValue, newValue):
+	case newValue == nil:
+		d.add(ChangeLevelNonBreaking, path, "%s has been removed", name)
+	case oldValue == nil:
+		d.add(ChangeLevelBreaking, path, "%s has been set to %v", name, *newValue)
+	case *newValue < *oldValue:
+		d.add(ChangeLevelBreaking, path, "%s changed from %v to %v", name, *oldValue, *newValue)
+	default:
+		d.add(ChangeLevelNonBreaking, path, "%s changed from %v to %v", name, *oldValue, *newValue)
+	}
+}
+
+// diffLowerBound reports changes of a bound where nil means unset
+// and raising the value restricts what is accepted.
+func (d *differ) diffLowerBound(path string, name string, oldValue *float64, newValue *float64) {
+
+	switch {
+	case equalBounds(oldValue, newValue):
+	case newValue == nil:
+		d.add(ChangeLevelNonBreaking, path, "%s has been removed", name)
+	case oldValue == nil:
+		d.add(ChangeLevelBreaking, path, "%s has been set to %v", name, *newValue)
+	case *newValue > *oldValue:
+		d.add(ChangeLevelBreaking, path, "%s changed from %v to %v", name, *oldValue, *newValue)
+	default:
+		d.add(ChangeLevelNonBreaking, path, "%s changed from %v to %v", name, *oldValue, *newValue)
+	}
+}
+
+// diffLengthLowerBound reports changes of a length lower bound. As a
+// length cannot be negative, setting it to 0 does not restrict what is
+// accepted.
+func (d *differ) diffLengthLowerBound(path string, name string, oldValue *uint16, newValue *uint16) {
+
+	if oldValue == nil && newValue != nil && *newValue == 0 {
+		d.add(ChangeLevelNonBreaking, path, "%s has been set to 0", name)
+		return
+	}
+
+	d.diffLowerBound(path, name, lengthBound(oldValue), lengthBound(newValue))
+}
+
+// diffExclusiveBound reports changes of the exclusivity of a bound.
+func (d *differ) diffExclusiveBound(path string, name string, oldExclusive bool, newExclusive bool) {
+
+	switch {
+	case !oldExclusive && newExclusive:
+		d.add(ChangeLevelBreaking, path, "%s became exclusive", name)
+	case oldExclusive && !newExclusive:
+		d.add(ChangeLevelNonBreaking, path, "%s is not exclusive anymore", name)
+	}
+}
+
+// diffMultipleOf reports changes of multiple_of. A new value only
+// relaxes the constraint if the old value is a multiple of it.
+func (d *differ) diffMultipleOf(path string, oldValue *float64, newValue *float64) {
+
+	switch {
+	case equalBounds(oldValue, newValue):
+	case newValue == nil:
+		d.add(ChangeLevelNonBreaking, path, "multiple_of has been removed")
+	case oldValue == nil:
+		d.add(ChangeLevelBreaking, path, "multiple_of has been set to %v", *newValue)
+	case math.Mod(*oldValue, *newValue) == 0:
+		d.add(ChangeLevelNonBreaking, path, "multiple_of changed from %v to %v", *oldValue, *newValue)
+	default:
+		d.add(ChangeLevelBreaking, path, "multiple_of changed from %v to %v", *oldValue, *newValue)
+	}
+}
+
+// equalBounds returns true if the given bounds are both
+// unset or both set to the same value.
+func equalBounds(a *float64, b *float64) bool {
+
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// lengthBound returns the given length bound as a value bound.
+func lengthBound(v *uint16) *float64 {
+
+	if v == nil {
+		return nil
+	}
+
+	f := float64(*v)
+
+	return &f
+}
+
+func (d *differ) diffRelationActions(path string, action string, oldAction *RelationAction, newAction *RelationAction) {
+
+	switch {
+	case oldAction == nil && newAction == nil:
+		return
+	case oldAction == nil:
+		d.add(ChangeLevelNonBreaking, path, "action '%s' has been added", action)
+		return
+	case newAction == nil:
+		d.add(ChangeLevelBreaking, path, "action '%s' has been removed", action)
+		return
+	}
+
+	if !oldAction.Deprecated && newAction.Deprecated {
+		d.add(ChangeLevelInformational, path, "action '%s' has been deprecated", action)
+	}
+
+	oldParams := parametersByName(oldAction.ParameterDefinition)
+	newParams := parametersByName(newAction.ParameterDefinition)
+
+	for name, p := range oldParams {
+
+		paramPath := fmt.Sprintf("%s: %s parameter '%s'", path, action, name)
+
+		newParam, ok := newParams[name]
+		if !ok {
+			d.add(ChangeLevelBreaking, paramPath, "parameter has been removed")
+			continue
+		}
+
+		if p.Type != newParam.Type {
+			d.add(ChangeLevelBreaking, paramPath, "type changed from '%s' to '%s'", p.Type, newParam.Type)
+		}
+
+		if p.Multiple && !newParam.Multiple {
+			d.add(ChangeLevelBreaking, paramPath, "parameter cannot be set multiple times anymore")
+		}
+
+		d.diffChoices(paramPath, p.AllowedChoices, newParam.AllowedChoices)
+		d.diffDeprecatedChoices(paramPath, p.AllowedChoices, p.Choices(), newParam.Choices())
+	}
+
+	for name := range newParams {
+		if _, ok := oldParams[name]; !ok {
+			d.add(ChangeLevelNonBreaking, fmt.Sprintf("%s: %s parameter '%s'", path, action, name), "parameter has been added")
+		}
+	}
+
+	if newAction.ParameterDefinition == nil {
+		return
+	}
+
+	var oldGroups [][][]string
+	if oldAction.ParameterDefinition != nil {
+		oldGroups = oldAction.ParameterDefinition.Required
+	}
+
+	for _, group := range newAction.ParameterDefinition.Required {
+
+		if requirementImplied(oldGroups, group) {
+			continue
+		}
+
+		if len(group) != 1 {
+			d.add(ChangeLevelBreaking, fmt.Sprintf("%s: %s parameters", path, action), "required parameters have been narrowed to %v", group)
+			continue
+		}
+
+		for _, name := range group[0] {
+			if !requirementImplied(oldGroups, [][]string{{name}}) {
+				d.add(ChangeLevelBreaking, fmt.Sprintf("%s: %s parameter '%s'", path, action, name), "parameter became required")
+			}
+		}
+	}
+}
+
+// requirementImplied returns true if satisfying the given required groups
+// always satisfies the given group. A group is satisfied by any of its
+// combinations, and a combination by setting all of its parameters.
+func requirementImplied(groups [][][]string, group [][]string) bool {
+
+	for _, candidate := range groups {
+
+		implied := len(candidate) > 0
+
+		for _, oldCombination := range candidate {
+
+			var found bool
+			for _, combination := range group {
+				if isSubset(combination, oldCombination) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				implied = false
+				break
+			}
+		}
+
+		if implied {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSubset returns true if all the values of
+// subset are contained in the given list.
+func isSubset(subset []string, list []string) bool {
+
+	for _, v := range subset {
+		if !containsString(list, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parametersByName(pd *ParameterDefinition) map[string]*Parameter {
+
+	out := map[string]*Parameter{}
+
+	if pd == nil {
+		return out
+	}
+
+	for _, p := range pd.Entries {
+		out[p.Name] = p
+	}
+
+	return out
+}
+
+func containsString(list []string, value string) bool {
+
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,633 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func makeTestSet(specs ...string) SpecificationSet {
+
+	set := &specificationSet{
+		specs: map[string]Specification{},
+	}
+
+	for _, data := range specs {
+		s := NewSpecification()
+		if err := s.Read(strings.NewReader(data), false); err != nil {
+			panic(err)
+		}
+		set.specs[s.Model().RestName] = s
+	}
+
+	return set
+}
+
+func TestDiff_DiffSpecificationSets(t *testing.T) {
+
+	Convey("Given I have two identical sets", t, func() {
+
+		oldSet, _ := LoadSpecificationSet("./tests", nil, nil, "")
+		newSet, _ := LoadSpecificationSet("./tests", nil, nil, "")
+
+		Convey("When I diff them", func() {
+
+			changes := DiffSpecificationSets(oldSet, newSet)
+
+			Convey("Then there should be no change", func() {
+				So(changes, ShouldBeEmpty)
+				So(changes.Breaking(), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given I have two different sets", t, func() {
+
+		oldSet := makeTestSet(
+			`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+  get:
+    description: Gets.
+    parameters:
+      entries:
+      - name: p1
+        type: string
+      - name: p2
+        type: enum
+        allowed_choices: [A, B]
+attributes:
+  v1:
+  - name: name
+    type: string
+    exposed: true
+  - name: status
+    type: enum
+    exposed: true
+    allowed_choices: [DONE, TODO]
+  - name: count
+    type: integer
+    exposed: true
+    max_value: 10
+  - name: internal
+    type: string
+relations:
+- rest_name: comment
+  get:
+    description: Gets.
+  create:
+    description: Creates.
+- rest_name: user
+  get:
+    description: Gets.
+`,
+			`model:
+  rest_name: removed
+  resource_name: removeds
+  entity_name: Removed
+  description: Removed.
+`,
+		)
+
+		newSet := makeTestSet(
+			`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A new task.
+  private: true
+  get:
+    description: Gets.
+    parameters:
+      required:
+      - - - p3
+      entries:
+      - name: p1
+        type: integer
+      - name: p2
+        type: enum
+        allowed_choices: [A, C]
+      - name: p3
+        type: string
+attributes:
+  v1:
+  - name: name
+    type: string
+    exposed: true
+    required: true
+  - name: status
+    type: enum
+    exposed: true
+    allowed_choices: [DONE, NEW]
+  - name: count
+    type: float
+    exposed: true
+    max_value: 5
+  - name: extra
+    type: string
+    exposed: true
+relations:
+- rest_name: comment
+  get:
+    description: Gets.
+- rest_name: label
+  get:
+    description: Gets.
+`,
+			`model:
+  rest_name: added
+  resource_name: addeds
+  entity_name: Added
+  description: Added.
+`,
+		)
+
+		Convey("When I diff them", func() {
+
+			changes := DiffSpecificationSets(oldSet, newSet)
+
+			Convey("Then the changes should be correct", func() {
+
+				out := make([]string, len(changes))
+				for i, c := range changes {
+					out[i] = c.String()
+				}
+
+				So(changes.Breaking(), ShouldBeTrue)
+				So(out, ShouldResemble, []string{
+					"non-breaking: added.spec: specification has been added",
+					"breaking: removed.spec: specification has been removed",
+					"breaking: task.spec: specification became private",
+					"informational: task.spec: description changed",
+					"breaking: task.spec: attribute 'count': type changed from 'integer' to 'float'",
+					"breaking: task.spec: attribute 'count': max_value changed from 10 to 5",
+					"non-breaking: task.spec: attribute 'extra': attribute has been added",
+					"informational: task.spec: attribute 'internal': unexposed attribute has been removed",
+					"breaking: task.spec: attribute 'name': attribute became required",
+					"breaking: task.spec: attribute 'status': allowed choice 'TODO' has been removed",
+					"non-breaking: task.spec: attribute 'status': allowed choice 'NEW' has been added",
+					"breaking: task.spec: get parameter 'p1': type changed from 'string' to 'integer'",
+					"breaking: task.spec: get parameter 'p2': allowed choice 'B' has been removed",
+					"non-breaking: task.spec: get parameter 'p2': allowed choice 'C' has been added",
+					"non-breaking: task.spec: get parameter 'p3': parameter has been added",
+					"breaking: task.spec: get parameter 'p3': parameter became required",
+					"breaking: task.spec: relation 'comment': action 'create' has been removed",
+					"non-breaking: task.spec: relation 'label': relation has been added",
+					"breaking: task.spec: relation 'user': relation has been removed",
+				})
+
+				So(len(changes.Level(ChangeLevelInformational)), ShouldEqual, 2)
+			})
+		})
+	})
+
+	Convey("Given I have two sets with different bounds", t, func() {
+
+		oldSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+attributes:
+  v1:
+  - name: count
+    type: integer
+    exposed: true
+    min_value: 1
+    max_value: 10
+    multiple_of: 2
+  - name: name
+    type: string
+    exposed: true
+    max_length: 0
+  - name: ratio
+    type: float
+    exposed: true
+    min_value: 0
+    exclusive_min_value: true
+    multiple_of: 4
+`)
+
+		newSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+attributes:
+  v1:
+  - name: count
+    type: integer
+    exposed: true
+    min_value: 0
+    max_value: 10
+    exclusive_max_value: true
+    multiple_of: 4
+  - name: name
+    type: string
+    exposed: true
+    min_length: 0
+  - name: ratio
+    type: float
+    exposed: true
+    min_value: 0
+    multiple_of: 2
+`)
+
+		Convey("When I diff them", func() {
+
+			changes := DiffSpecificationSets(oldSet, newSet)
+
+			Convey("Then the changes should be correct", func() {
+
+				out := make([]string, len(changes))
+				for i, c := range changes {
+					out[i] = c.String()
+				}
+
+				So(out, ShouldResemble, []string{
+					"non-breaking: task.spec: attribute 'count': min_value changed from 1 to 0",
+					"breaking: task.spec: attribute 'count': max_value became exclusive",
+					"breaking: task.spec: attribute 'count': multiple_of changed from 2 to 4",
+					"non-breaking: task.spec: attribute 'name': max_length has been removed",
+					"non-breaking: task.spec: attribute 'name': min_length has been set to 0",
+					"non-breaking: task.spec: attribute 'ratio': min_value is not exclusive anymore",
+					"non-breaking: task.spec: attribute 'ratio': multiple_of changed from 4 to 2",
+				})
+			})
+		})
+	})
+
+	Convey("Given I have two sets with different list constraints", t, func() {
+
+		oldSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+attributes:
+  v1:
+  - name: labels
+    type: list
+    subtype: string
+    exposed: true
+    max_items: 10
+    unique_items: true
+    items:
+      allowed_choices:
+      - a
+      - b
+      max_length: 5
+  - name: tags
+    type: list
+    subtype: string
+    exposed: true
+`)
+
+		newSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+attributes:
+  v1:
+  - name: labels
+    type: list
+    subtype: string
+    exposed: true
+    max_items: 20
+    items:
+      allowed_choices:
+      - a
+      max_length: 5
+  - name: tags
+    type: list
+    subtype: string
+    exposed: true
+    min_items: 1
+    items:
+      allowed_chars: ^[a-z]+$
+      allowed_chars_message: lowercase
+`)
+
+		Convey("When I diff them", func() {
+
+			changes := DiffSpecificationSets(oldSet, newSet)
+
+			Convey("Then the changes should be correct", func() {
+
+				out := make([]string, len(changes))
+				for i, c := range changes {
+					out[i] = c.String()
+				}
+
+				So(out, ShouldResemble, []string{
+					"non-breaking: task.spec: attribute 'labels': max_items changed from 10 to 20",
+					"non-breaking: task.spec: attribute 'labels': items do not need to be unique anymore",
+					"breaking: task.spec: attribute 'labels' items: allowed choice 'b' has been removed",
+					"breaking: task.spec: attribute 'tags': min_items has been set to 1",
+					"breaking: task.spec: attribute 'tags' items: allowed_chars changed from '' to '^[a-z]+$'",
+				})
+			})
+		})
+	})
+
+	Convey("Given I have two sets with different map constraints", t, func() {
+
+		oldSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+attributes:
+  v1:
+  - name: counters
+    type: map
+    subtype: integer
+    exposed: true
+    key_allowed_chars: ^[a-z]+$
+  - name: labels
+    type: map
+    subtype: string
+    exposed: true
+`)
+
+		newSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+attributes:
+  v1:
+  - name: counters
+    type: map
+    subtype: float
+    exposed: true
+  - name: labels
+    type: map
+    subtype: string
+    exposed: true
+    key_allowed_chars: ^[a-z]+$
+`)
+
+		Convey("When I diff them", func() {
+
+			changes := DiffSpecificationSets(oldSet, newSet)
+
+			Convey("Then the changes should be correct", func() {
+
+				out := make([]string, len(changes))
+				for i, c := range changes {
+					out[i] = c.String()
+				}
+
+				So(out, ShouldResemble, []string{
+					"breaking: task.spec: attribute 'counters': subtype changed from 'integer' to 'float'",
+					"non-breaking: task.spec: attribute 'counters' keys: allowed_chars has been removed",
+					"breaking: task.spec: attribute 'labels' keys: allowed_chars changed from '' to '^[a-z]+$'",
+				})
+			})
+		})
+	})
+
+	Convey("Given I have two sets with deprecated enum values", t, func() {
+
+		oldSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+attributes:
+  v1:
+  - name: status
+    type: enum
+    exposed: true
+    allowed_choices:
+    - DONE
+    - HOLD
+    - TODO
+`)
+
+		newSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+attributes:
+  v1:
+  - name: status
+    type: enum
+    exposed: true
+    enum_values:
+    - value: DONE
+      description: Done.
+    - value: HOLD
+      deprecated: true
+    - value: TODO
+`)
+
+		So(newSet.Specification("task").Attribute("status", "v1").resolveEnum(nil), ShouldBeNil)
+
+		Convey("When I diff them", func() {
+
+			changes := DiffSpecificationSets(oldSet, newSet)
+
+			Convey("Then the changes should be correct", func() {
+
+				out := make([]string, len(changes))
+				for i, c := range changes {
+					out[i] = c.String()
+				}
+
+				So(out, ShouldResemble, []string{
+					"informational: task.spec: attribute 'status': allowed choice 'HOLD' has been deprecated",
+				})
+			})
+		})
+	})
+
+	Convey("Given I have two sets with different nested properties", t, func() {
+
+		oldSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+attributes:
+  v1:
+  - name: address
+    type: object
+    exposed: true
+    properties:
+    - name: city
+      type: string
+    - name: street
+      type: string
+      max_length: 64
+    - name: zip
+      type: string
+`)
+
+		newSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+attributes:
+  v1:
+  - name: address
+    type: object
+    exposed: true
+    properties:
+    - name: city
+      type: string
+      required: true
+    - name: country
+      type: string
+    - name: number
+      type: integer
+      required: true
+    - name: street
+      type: string
+`)
+
+		Convey("When I diff them", func() {
+
+			changes := DiffSpecificationSets(oldSet, newSet)
+
+			Convey("Then the changes should be correct", func() {
+
+				out := make([]string, len(changes))
+				for i, c := range changes {
+					out[i] = c.String()
+				}
+
+				So(out, ShouldResemble, []string{
+					"breaking: task.spec: attribute 'address' property 'city': attribute became required",
+					"non-breaking: task.spec: attribute 'address' property 'country': property has been added",
+					"breaking: task.spec: attribute 'address' property 'number': required property has been added",
+					"non-breaking: task.spec: attribute 'address' property 'street': max_length has been removed",
+					"breaking: task.spec: attribute 'address' property 'zip': property has been removed",
+				})
+			})
+		})
+	})
+
+	Convey("Given I have two sets with different required parameters", t, func() {
+
+		oldSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+  get:
+    description: Gets.
+    parameters:
+      required:
+      - - - a
+        - - b
+      entries:
+      - name: a
+        type: string
+      - name: b
+        type: string
+  delete:
+    description: Deletes.
+    parameters:
+      required:
+      - - - a
+        - - b
+        - - c
+      entries:
+      - name: a
+        type: string
+      - name: b
+        type: string
+      - name: c
+        type: string
+  update:
+    description: Updates.
+    parameters:
+      required:
+      - - - a
+      entries:
+      - name: a
+        type: string
+      - name: b
+        type: string
+`)
+
+		newSet := makeTestSet(`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+  get:
+    description: Gets.
+    parameters:
+      required:
+      - - - a
+      entries:
+      - name: a
+        type: string
+      - name: b
+        type: string
+  delete:
+    description: Deletes.
+    parameters:
+      required:
+      - - - a
+        - - b
+          - c
+      entries:
+      - name: a
+        type: string
+      - name: b
+        type: string
+      - name: c
+        type: string
+  update:
+    description: Updates.
+    parameters:
+      required:
+      - - - a
+        - - b
+      entries:
+      - name: a
+        type: string
+      - name: b
+        type: string
+`)
+
+		Convey("When I diff them", func() {
+
+			changes := DiffSpecificationSets(oldSet, newSet)
+
+			Convey("Then only the narrowed requirements should be reported", func() {
+
+				out := make([]string, len(changes))
+				for i, c := range changes {
+					out[i] = c.String()
+				}
+
+				So(out, ShouldResemble, []string{
+					"breaking: task.spec: delete parameters: required parameters have been narrowed to [[a] [b c]]",
+					"breaking: task.spec: get parameter 'a': parameter became required",
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,215 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// An EnumValue represents a documented value of an enum.
+type EnumValue struct {
+	Value       string `yaml:"value,omitempty"          json:"value,omitempty"`
+	Description string `yaml:"description,omitempty"    json:"description,omitempty"`
+	Deprecated  bool   `yaml:"deprecated,omitempty"     json:"deprecated,omitempty"`
+}
+
+// An EnumDefinition represents a named enum declared
+// once and referenced by attributes and parameters.
+type EnumDefinition struct {
+	Description string       `yaml:"description,omitempty"    json:"description,omitempty"`
+	Values      []*EnumValue `yaml:"values,omitempty"         json:"values,omitempty"`
+}
+
+// An EnumMapping holds the named enums indexed by name.
+type EnumMapping map[string]*EnumDefinition
+
+// NewEnumMapping returns a new EnumMapping.
+func NewEnumMapping() EnumMapping {
+	return EnumMapping{}
+}
+
+// LoadEnumMapping loads an EnumMapping from the given file.
+func LoadEnumMapping(path string) (EnumMapping, error) {
+
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G307
+	defer file.Close() // nolint: errcheck
+
+	em := NewEnumMapping()
+
+	if err = em.Read(file, true); err != nil {
+		return nil, err
+	}
+
+	return em, nil
+}
+
+// Read loads an enum mapping from the given io.Reader
+func (e EnumMapping) Read(reader io.Reader, validate bool) (err error) {
+
+	decoder := yaml.NewDecoder(reader)
+	decoder.SetStrict(true)
+
+	if err = decoder.Decode(&e); err != nil {
+		return err
+	}
+
+	if validate {
+		if errs := e.Validate(); len(errs) != 0 {
+			return formatValidationErrors(errs)
+		}
+	}
+
+	return nil
+}
+
+// Write dumps the enum mapping into the given writer.
+func (e EnumMapping) Write(writer io.Writer) error {
+
+	repr := yaml.MapSlice{}
+
+	for _, k := range sortedKeys(e) {
+		repr = append(repr, yaml.MapItem{
+			Key:   k,
+			Value: e[k],
+		})
+	}
+
+	data, err := yaml.Marshal(repr)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	lines := bytes.Split(data, []byte("\n"))
+
+	for i, line := range lines {
+
+		if i > 0 && len(line) > 0 && line[0] != ' ' {
+			_, _ = buf.WriteRune('\n')
+		}
+
+		_, _ = buf.Write(line)
+
+		if i+1 < len(lines) {
+			_, _ = buf.WriteRune('\n')
+		}
+	}
+
+	_, err = writer.Write(buf.Bytes())
+	return err
+}
+
+// Validate validates the EnumMapping.
+func (e EnumMapping) Validate() []error {
+
+	var errs []error
+
+	for _, name := range sortedKeys(e) {
+
+		if e[name] == nil || len(e[name].Values) == 0 {
+			errs = append(errs, fmt.Errorf("_enums.mapping: enum '%s' must define values", name))
+			continue
+		}
+
+		errs = append(errs, validateEnumValues(e[name].Values, fmt.Sprintf("_enums.mapping: enum '%s'", name))...)
+	}
+
+	return errs
+}
+
+// validateEnumDeclaration validates how the given attribute or parameter
+// declares its enum values. An enum must declare them with exactly
+// one of allowed_choices, enum_values or enum_reference.
+func validateEnumDeclaration(file string, kind string, name string, isEnum bool, choices []string, values []*EnumValue, reference string) []error {
+
+	var declared int
+	for _, ok := range []bool{len(choices) > 0, len(values) > 0, reference != ""} {
+		if ok {
+			declared++
+		}
+	}
+
+	switch {
+	case !isEnum && (len(values) > 0 || reference != ""):
+		return []error{fmt.Errorf("%s: %s '%s' is not an enum but defines enum_values or enum_reference", file, kind, name)}
+	case !isEnum:
+		return nil
+	case declared == 0:
+		return []error{fmt.Errorf("%s: enum %s '%s' must define allowed_choices, enum_values or enum_reference", file, kind, name)}
+	case declared > 1:
+		return []error{fmt.Errorf("%s: enum %s '%s' must define only one of allowed_choices, enum_values or enum_reference", file, kind, name)}
+	}
+
+	return validateEnumValues(values, fmt.Sprintf("%s: enum %s '%s'", file, kind, name))
+}
+
+// validateEnumValues validates the given enum values.
+// The errors are prefixed by the given owner.
+func validateEnumValues(values []*EnumValue, owner string) []error {
+
+	var errs []error
+
+	seen := map[string]struct{}{}
+
+	for _, v := range values {
+
+		if v.Value == "" {
+			errs = append(errs, fmt.Errorf("%s has an entry without value", owner))
+			continue
+		}
+
+		if _, ok := seen[v.Value]; ok {
+			errs = append(errs, fmt.Errorf("%s has duplicate value '%s'", owner, v.Value))
+		}
+
+		seen[v.Value] = struct{}{}
+	}
+
+	return errs
+}
+
+// resolveEnumValues returns the enum values declared inline or by
+// the named enum with the given reference. It returns nil if there
+// is none.
+func resolveEnumValues(enums EnumMapping, values []*EnumValue, reference string) ([]*EnumValue, error) {
+
+	if reference == "" {
+		return values, nil
+	}
+
+	def, ok := enums[reference]
+	if !ok || def == nil {
+		return nil, fmt.Errorf("unable to find enum '%s'", reference)
+	}
+
+	return def.Values, nil
+}
+
+// enumChoices returns the values of the given enum values.
+func enumChoices(values []*EnumValue) []string {
+
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = v.Value
+	}
+
+	return out
+}
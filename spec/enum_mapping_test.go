@@ -0,0 +1,230 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testEnumMapping = `priority:
+  description: The priority of a task.
+  values:
+  - value: High
+    description: Must be done first.
+  - value: Low
+
+status:
+  values:
+  - value: DONE
+    description: The task is done.
+  - value: HOLD
+    description: The task is on hold.
+    deprecated: true
+  - value: TODO
+    description: The task must be done.
+`
+
+const testEnumStatus = `    allowed_choices:
+    - DONE
+    - PROGRESS
+    - TODO
+`
+
+func TestEnumMapping_Read(t *testing.T) {
+
+	Convey("Given I read an enum mapping", t, func() {
+
+		em := NewEnumMapping()
+		err := em.Read(strings.NewReader(testEnumMapping), true)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the mapping should be correct", func() {
+			So(len(em), ShouldEqual, 2)
+			So(em["priority"].Description, ShouldEqual, "The priority of a task.")
+			So(enumChoices(em["status"].Values), ShouldResemble, []string{"DONE", "HOLD", "TODO"})
+			So(em["status"].Values[1].Deprecated, ShouldBeTrue)
+		})
+
+		Convey("When I write it", func() {
+
+			buf := &bytes.Buffer{}
+			err := em.Write(buf)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the output should be correct", func() {
+				So(buf.String(), ShouldEqual, testEnumMapping)
+			})
+		})
+	})
+
+	Convey("Given I read invalid enum mappings", t, func() {
+
+		tests := map[string]string{
+			"empty:\n  description: Nothing.\n":             "_enums.mapping: enum 'empty' must define values",
+			"dup:\n  values:\n  - value: A\n  - value: A\n": "_enums.mapping: enum 'dup' has duplicate value 'A'",
+			"novalue:\n  values:\n  - description: A.\n":    "_enums.mapping: enum 'novalue' has an entry without value",
+		}
+
+		for data, expected := range tests {
+
+			err := NewEnumMapping().Read(strings.NewReader(data), true)
+
+			Convey("Then reading '"+expected+"' should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, expected)
+			})
+		}
+	})
+}
+
+func TestEnumMapping_LoadSpecificationSet(t *testing.T) {
+
+	task, _ := os.ReadFile("./tests/task.spec")
+	root, _ := os.ReadFile("./tests/root.spec")
+
+	rootWithEnum := strings.Replace(
+		string(root),
+		"      - name: rlgmp2\n        description: this is rlgmp2.\n        type: boolean\n",
+		"      - name: rlgmp2\n        description: this is rlgmp2.\n        type: enum\n        enum_reference: priority\n",
+		1,
+	)
+
+	Convey("Given I have a spec folder with named enums", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"_enums.mapping": testEnumMapping,
+			"task.spec":      strings.Replace(string(task), testEnumStatus, "    enum_reference: status\n", 1),
+			"root.spec":      rootWithEnum,
+		})
+
+		Convey("When I load it", func() {
+
+			set, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the attribute should use the values of the enum", func() {
+				attr := set.Specification("task").Attribute("status", "v1")
+				So(attr.AllowedChoices, ShouldResemble, []string{"DONE", "HOLD", "TODO"})
+				So(attr.Choices(), ShouldResemble, set.(DefinitionProvider).EnumMapping()["status"].Values)
+			})
+
+			Convey("Then the parameter should use the values of the enum", func() {
+				p := set.Specification("root").Relation("list").Get.ParameterDefinition.Entries[1]
+				So(p.AllowedChoices, ShouldResemble, []string{"High", "Low"})
+				So(p.Choices(), ShouldResemble, set.(DefinitionProvider).EnumMapping()["priority"].Values)
+			})
+
+			Convey("Then writing the task should keep the reference", func() {
+				buf := &bytes.Buffer{}
+				So(set.Specification("task").Write(buf), ShouldBeNil)
+				So(buf.String(), ShouldContainSubstring, "    type: enum\n    exposed: true\n    stored: true\n    enum_reference: status\n    default_value: TODO\n")
+			})
+
+			Convey("Then writing the root should keep the reference", func() {
+				buf := &bytes.Buffer{}
+				So(set.Specification("root").Write(buf), ShouldBeNil)
+				So(buf.String(), ShouldContainSubstring, "        type: enum\n        enum_reference: priority\n")
+				So(buf.String(), ShouldNotContainSubstring, "- High")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with documented enum values", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"task.spec": strings.Replace(
+				string(task),
+				testEnumStatus,
+				"    enum_values:\n    - value: DONE\n      description: The task is done.\n    - value: TODO\n",
+				1,
+			),
+		})
+
+		Convey("When I load it", func() {
+
+			set, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the attribute should use the documented values", func() {
+				attr := set.Specification("task").Attribute("status", "v1")
+				So(attr.AllowedChoices, ShouldResemble, []string{"DONE", "TODO"})
+				So(attr.Choices()[0].Description, ShouldEqual, "The task is done.")
+			})
+		})
+
+		Convey("When I read the task and write it back", func() {
+
+			s, err := LoadSpecification(filepath.Join(dir, "task.spec"), true)
+			So(err, ShouldBeNil)
+
+			buf := &bytes.Buffer{}
+			So(s.Write(buf), ShouldBeNil)
+
+			Convey("Then the documented values should be kept", func() {
+				So(buf.String(), ShouldContainSubstring, "    enum_values:\n    - value: DONE\n      description: The task is done.\n    - value: TODO\n")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder referencing a missing enum", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"task.spec": strings.Replace(string(task), testEnumStatus, "    enum_reference: status\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "task.spec: attribute 'status': unable to find enum 'status'")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with an enum declared twice", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"_enums.mapping": testEnumMapping,
+			"task.spec":      strings.Replace(string(task), testEnumStatus, testEnumStatus+"    enum_reference: status\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "task.spec: enum attribute 'status' must define only one of allowed_choices, enum_values or enum_reference")
+			})
+		})
+	})
+}
@@ -0,0 +1,362 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	ini "gopkg.in/ini.v1"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// extensionSectionPrefix is the prefix of the regolithe.ini
+// sections declaring an extension.
+const extensionSectionPrefix = "extension."
+
+// ExtensionTarget represents the kind of object an extension applies to.
+type ExtensionTarget string
+
+// Various values for ExtensionTarget.
+const (
+	ExtensionTargetModel     ExtensionTarget = "model"
+	ExtensionTargetAttribute ExtensionTarget = "attribute"
+	ExtensionTargetRelation  ExtensionTarget = "relation"
+)
+
+// An ExtensionDefinition declares an extension key.
+type ExtensionDefinition struct {
+	Description string            `yaml:"description,omitempty"    json:"description,omitempty"`
+	Targets     []ExtensionTarget `yaml:"targets,omitempty"        json:"targets,omitempty"`
+	Schema      map[string]any    `yaml:"schema,omitempty"         json:"schema,omitempty"`
+}
+
+// ExtensionMapping holds the declared extension keys.
+type ExtensionMapping map[string]*ExtensionDefinition
+
+// builtinExtensions holds the extensions used by regolithe itself.
+// They are always declared and cannot be redefined.
+var builtinExtensions = ExtensionMapping{
+	"forceDocumentation": {
+		Description: "If set to anything but false, the private specification will be documented.",
+		Targets:     []ExtensionTarget{ExtensionTargetModel},
+	},
+	lintExtensionKey: {
+		Description: "List of lint rule identifiers to suppress, or 'all'.",
+		Targets:     []ExtensionTarget{ExtensionTargetModel, ExtensionTargetAttribute},
+		Schema: map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "string",
+			},
+		},
+	},
+}
+
+// NewExtensionMapping returns a new ExtensionMapping
+// containing the built-in extensions.
+func NewExtensionMapping() ExtensionMapping {
+
+	em := ExtensionMapping{}
+	for k, v := range builtinExtensions {
+		em[k] = v
+	}
+
+	return em
+}
+
+// LoadExtensionMapping loads an ExtensionMapping from the given file.
+func LoadExtensionMapping(path string) (ExtensionMapping, error) {
+
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G307
+	defer file.Close() // nolint: errcheck
+
+	em := NewExtensionMapping()
+
+	if err = em.Read(file, true); err != nil {
+		return nil, err
+	}
+
+	return em, nil
+}
+
+// Read loads an extension mapping from the given io.Reader.
+func (e ExtensionMapping) Read(reader io.Reader, validate bool) (err error) {
+
+	decoded := ExtensionMapping{}
+
+	decoder := yaml.NewDecoder(reader)
+	decoder.SetStrict(true)
+
+	if err = decoder.Decode(&decoded); err != nil {
+		return err
+	}
+
+	for k, v := range decoded {
+
+		if _, ok := builtinExtensions[k]; ok {
+			return fmt.Errorf("_extensions.mapping: extension '%s' is built-in and cannot be redefined", k)
+		}
+
+		if v != nil && v.Schema != nil {
+			v.Schema = massageYAML(v.Schema).(map[string]any)
+		}
+
+		e[k] = v
+	}
+
+	if validate {
+		if errs := e.Validate(); len(errs) != 0 {
+			return formatValidationErrors(errs)
+		}
+	}
+
+	return nil
+}
+
+// Write dumps the extension mapping, without the
+// built-in extensions, into the given writer.
+func (e ExtensionMapping) Write(writer io.Writer) error {
+
+	repr := yaml.MapSlice{}
+
+	for _, k := range sortedKeys(e) {
+
+		if _, ok := builtinExtensions[k]; ok {
+			continue
+		}
+
+		def := e[k]
+
+		var item yaml.MapSlice
+		if def != nil {
+			item = toYAMLMapSlice(def)
+		}
+
+		repr = append(repr, yaml.MapItem{
+			Key:   k,
+			Value: item,
+		})
+	}
+
+	data, err := yaml.Marshal(repr)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	lines := bytes.Split(data, []byte("\n"))
+
+	for i, line := range lines {
+		condFirstLine := i == 0
+
+		if !condFirstLine && len(line) > 0 && line[0] != ' ' {
+			_, _ = buf.WriteRune('\n')
+		}
+
+		_, _ = buf.Write(line)
+
+		if i+1 < len(lines) {
+			_, _ = buf.WriteRune('\n')
+		}
+	}
+
+	_, err = writer.Write(buf.Bytes())
+	return err
+}
+
+// Validate validates the extension mapping against the schema.
+func (e ExtensionMapping) Validate() []error {
+
+	return e.validate("_extensions.mapping")
+}
+
+// validate validates the extension mapping declared
+// in the given source against the schema.
+func (e ExtensionMapping) validate(source string) []error {
+
+	schemaData, err := fs.ReadFile("schema/rego-extension-mapping.json")
+	if err != nil {
+		return []error{err}
+	}
+
+	schemaLoader := gojsonschema.NewBytesLoader(schemaData)
+	specLoader := gojsonschema.NewGoLoader(e)
+
+	res, err := gojsonschema.Validate(schemaLoader, specLoader)
+	if err != nil {
+		return []error{err}
+	}
+
+	if !res.Valid() {
+		return makeSchemaValidationError(source, res.Errors())
+	}
+
+	var errs []error
+	for _, k := range sortedKeys(e) {
+
+		if e[k] == nil || e[k].Schema == nil {
+			continue
+		}
+
+		if _, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(e[k].Schema)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid schema for extension '%s': %s", source, k, err))
+		}
+	}
+
+	return errs
+}
+
+// check validates the given extensions set on the given target.
+// The owner is used in error messages. If strict is true, extensions
+// that are not declared are reported.
+func (e ExtensionMapping) check(target ExtensionTarget, owner string, extensions map[string]any, strict bool) []error {
+
+	var errs []error
+
+	for _, k := range sortedKeys(extensions) {
+
+		def, ok := e[k]
+		if !ok || def == nil {
+			if strict {
+				errs = append(errs, fmt.Errorf("%s: unknown extension '%s'", owner, k))
+			}
+			continue
+		}
+
+		if !def.allows(target) {
+			errs = append(errs, fmt.Errorf("%s: extension '%s' cannot be used on a %s", owner, k, target))
+			continue
+		}
+
+		if def.Schema == nil {
+			continue
+		}
+
+		res, err := gojsonschema.Validate(
+			gojsonschema.NewGoLoader(def.Schema),
+			gojsonschema.NewGoLoader(massageYAML(extensions[k])),
+		)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: unable to validate extension '%s': %s", owner, k, err))
+			continue
+		}
+
+		if !res.Valid() {
+			errs = append(errs, makeSchemaValidationError(fmt.Sprintf("%s: extension '%s'", owner, k), res.Errors())...)
+		}
+	}
+
+	return errs
+}
+
+// checkSpecification validates all the extensions of the given Specification.
+func (e ExtensionMapping) checkSpecification(s Specification, strict bool) []error {
+
+	model := s.Model()
+	if model == nil {
+		return nil
+	}
+
+	errs := e.check(ExtensionTargetModel, fmt.Sprintf("%s.spec: model", model.RestName), model.Extensions, strict)
+
+	for _, version := range sortVersionStrings(s.AttributeVersions()) {
+		for _, attr := range withProperties(s.Attributes(version)) {
+			errs = append(errs, e.check(ExtensionTargetAttribute, fmt.Sprintf("%s.spec: attribute '%s'", model.RestName, attr.qualifiedName()), attr.Extensions, strict)...)
+		}
+	}
+
+	for _, rel := range s.Relations() {
+		errs = append(errs, e.check(ExtensionTargetRelation, fmt.Sprintf("%s.spec: relation '%s'", model.RestName, rel.RestName), rel.Extensions, strict)...)
+	}
+
+	return errs
+}
+
+// loadExtensionSection returns the name and the definition of the
+// extension declared in the given [extension.<key>] section. The
+// targets key is a comma separated list and the schema key holds
+// a JSON schema.
+func loadExtensionSection(section *ini.Section) (string, *ExtensionDefinition, error) {
+
+	name := strings.TrimPrefix(section.Name(), extensionSectionPrefix)
+
+	if _, ok := builtinExtensions[name]; ok {
+		return "", nil, fmt.Errorf("regolithe.ini: extension '%s' is built-in and cannot be redefined", name)
+	}
+
+	def := &ExtensionDefinition{
+		Description: section.Key("description").String(),
+	}
+
+	if section.HasKey("targets") {
+		for _, t := range section.Key("targets").Strings(",") {
+			def.Targets = append(def.Targets, ExtensionTarget(t))
+		}
+	}
+
+	if section.HasKey("schema") {
+		if err := json.Unmarshal([]byte(section.Key("schema").String()), &def.Schema); err != nil {
+			return "", nil, fmt.Errorf("regolithe.ini: unable to decode the schema of extension '%s': %w", name, err)
+		}
+	}
+
+	return name, def, nil
+}
+
+func (d *ExtensionDefinition) allows(target ExtensionTarget) bool {
+
+	for _, t := range d.Targets {
+		if t == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExtensionValue returns the value of the extension with the given key
+// converted to T. It returns false if the extension is not set or if its
+// value cannot be converted to T.
+func ExtensionValue[T any](extensions map[string]any, key string) (T, bool) {
+
+	var out T
+
+	v, ok := extensions[key]
+	if !ok {
+		return out, false
+	}
+
+	if t, ok := v.(T); ok {
+		return t, true
+	}
+
+	data, err := json.Marshal(massageYAML(v))
+	if err != nil {
+		return out, false
+	}
+
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, false
+	}
+
+	return out, true
+}
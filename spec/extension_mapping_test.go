@@ -0,0 +1,394 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testExtensionMapping = `orderingKey:
+  description: The key used to order the objects.
+  targets:
+  - model
+  - relation
+  schema:
+    type: string
+    minLength: 1
+
+precision:
+  targets:
+  - attribute
+  schema:
+    type: object
+    properties:
+      digits:
+        type: integer
+    required:
+    - digits
+`
+
+func TestExtensionMapping_Read(t *testing.T) {
+
+	Convey("Given I read an extension mapping", t, func() {
+
+		em := NewExtensionMapping()
+		err := em.Read(strings.NewReader(testExtensionMapping), true)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the mapping should contain the declared and built-in extensions", func() {
+			So(len(em), ShouldEqual, 4)
+			So(em["orderingKey"].Targets, ShouldResemble, []ExtensionTarget{ExtensionTargetModel, ExtensionTargetRelation})
+			So(em["precision"].Schema["type"], ShouldEqual, "object")
+			So(em["forceDocumentation"], ShouldNotBeNil)
+			So(em["noLint"], ShouldNotBeNil)
+		})
+
+		Convey("When I write it", func() {
+
+			buf := &bytes.Buffer{}
+			err := em.Write(buf)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the built-in extensions should not be written", func() {
+				So(buf.String(), ShouldStartWith, "orderingKey:\n  description: The key used to order the objects.\n  targets:\n  - model\n  - relation\n")
+				So(buf.String(), ShouldNotContainSubstring, "noLint")
+			})
+		})
+	})
+
+	Convey("Given I read an extension mapping redefining a built-in extension", t, func() {
+
+		em := NewExtensionMapping()
+		err := em.Read(strings.NewReader("noLint:\n  targets:\n  - relation\n"), true)
+
+		Convey("Then err should not be nil", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "_extensions.mapping: extension 'noLint' is built-in and cannot be redefined")
+		})
+	})
+
+	Convey("Given I read an extension mapping with an invalid target", t, func() {
+
+		em := NewExtensionMapping()
+		err := em.Read(strings.NewReader("key:\n  targets:\n  - parameter\n"), true)
+
+		Convey("Then err should not be nil", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "_extensions.mapping: schema error: key.targets.0: key.targets.0 must be one of the following")
+		})
+	})
+}
+
+func TestExtensionMapping_check(t *testing.T) {
+
+	Convey("Given I have an extension mapping", t, func() {
+
+		em := NewExtensionMapping()
+		So(em.Read(strings.NewReader(testExtensionMapping), true), ShouldBeNil)
+
+		Convey("When I check valid extensions", func() {
+
+			errs := em.check(ExtensionTargetAttribute, "task.spec: attribute 'a'", map[string]any{
+				"precision": map[any]any{"digits": 2},
+				"noLint":    []any{"attribute-description-period"},
+			}, true)
+
+			Convey("Then errs should be empty", func() {
+				So(errs, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When I check invalid extensions in strict mode", func() {
+
+			errs := em.check(ExtensionTargetModel, "task.spec: model", map[string]any{
+				"orderingKey": "",
+				"precision":   map[any]any{"digits": 2},
+				"unknown":     true,
+			}, true)
+
+			Convey("Then errs should be correct", func() {
+				So(errorStrings(errs), ShouldResemble, []string{
+					"task.spec: model: extension 'orderingKey': schema error: (root): String length must be greater than or equal to 1",
+					"task.spec: model: extension 'precision' cannot be used on a model",
+					"task.spec: model: unknown extension 'unknown'",
+				})
+			})
+		})
+
+		Convey("When I check a legacy forceDocumentation extension", func() {
+
+			errs := em.check(ExtensionTargetModel, "task.spec: model", map[string]any{
+				"forceDocumentation": "yes",
+			}, true)
+
+			Convey("Then errs should be empty", func() {
+				So(errs, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When I check unknown extensions in non strict mode", func() {
+
+			errs := em.check(ExtensionTargetRelation, "task.spec: relation 'user'", map[string]any{
+				"orderingKey": "name",
+				"unknown":     true,
+			}, false)
+
+			Convey("Then errs should be empty", func() {
+				So(errs, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestExtensionMapping_ExtensionValue(t *testing.T) {
+
+	Convey("Given I have some extensions", t, func() {
+
+		extensions := map[string]any{
+			"bool":   true,
+			"list":   []any{"a", "b"},
+			"object": map[any]any{"digits": 2},
+		}
+
+		Convey("When I retrieve a bool", func() {
+
+			v, ok := ExtensionValue[bool](extensions, "bool")
+
+			Convey("Then the value should be correct", func() {
+				So(ok, ShouldBeTrue)
+				So(v, ShouldBeTrue)
+			})
+		})
+
+		Convey("When I retrieve a list of strings", func() {
+
+			v, ok := ExtensionValue[[]string](extensions, "list")
+
+			Convey("Then the value should be correct", func() {
+				So(ok, ShouldBeTrue)
+				So(v, ShouldResemble, []string{"a", "b"})
+			})
+		})
+
+		Convey("When I retrieve a struct", func() {
+
+			v, ok := ExtensionValue[struct {
+				Digits int `json:"digits"`
+			}](extensions, "object")
+
+			Convey("Then the value should be correct", func() {
+				So(ok, ShouldBeTrue)
+				So(v.Digits, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When I retrieve a value with the wrong type", func() {
+
+			_, ok := ExtensionValue[int](extensions, "list")
+
+			Convey("Then ok should be false", func() {
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("When I retrieve a missing value", func() {
+
+			_, ok := ExtensionValue[string](extensions, "missing")
+
+			Convey("Then ok should be false", func() {
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestExtensionMapping_LoadSpecificationSet(t *testing.T) {
+
+	task, _ := os.ReadFile("./tests/task.spec")
+
+	Convey("Given I have a spec folder with valid declared extensions", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"_extensions.mapping": testExtensionMapping,
+			"task.spec":           strings.Replace(string(task), "  extends:\n", "  extensions:\n    orderingKey: name\n  extends:\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			set, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the extension mapping should be available", func() {
+				So(set.(DefinitionProvider).ExtensionMapping()["orderingKey"], ShouldNotBeNil)
+				v, _ := ExtensionValue[string](set.Specification("task").Model().Extensions, "orderingKey")
+				So(v, ShouldEqual, "name")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with undeclared extensions", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"_extensions.mapping": testExtensionMapping,
+			"task.spec":           strings.Replace(string(task), "  extends:\n", "  extensions:\n    orderKey: name\n  extends:\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "task.spec: model: unknown extension 'orderKey'")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with undeclared extensions on nested properties", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"_extensions.mapping": testExtensionMapping,
+			"task.spec": strings.Replace(string(task), "  - name: description\n", `  - name: address
+    description: The address of the task.
+    type: object
+    exposed: true
+    properties:
+    - name: street
+      description: The street.
+      type: string
+      extensions:
+        orderKey: name
+
+  - name: description
+`, 1),
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "task.spec: attribute 'address.street': unknown extension 'orderKey'")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with undeclared extensions but no extension mapping", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"task.spec": strings.Replace(string(task), "  extends:\n", "  extensions:\n    orderKey: name\n    noLint: all\n  extends:\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then only the built-in extensions should be validated", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "task.spec: model: extension 'noLint': schema error: (root): Invalid type. Expected: array, given: string")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder declaring extensions in regolithe.ini", t, func() {
+
+		ini, _ := os.ReadFile("./tests/regolithe.ini")
+
+		dir := copyTestFolder(t, map[string]string{
+			"regolithe.ini": string(ini) + "\n[extension.orderingKey]\ndescription = The key used to order the objects.\ntargets = model, relation\nschema = {\"type\": \"string\", \"minLength\": 1}\n",
+			"task.spec":     strings.Replace(string(task), "  extends:\n", "  extensions:\n    orderingKey: name\n    orderKey: name\n  extends:\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then the extensions should be validated strictly", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "task.spec: model: unknown extension 'orderKey'")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder declaring an extension both in regolithe.ini and _extensions.mapping", t, func() {
+
+		ini, _ := os.ReadFile("./tests/regolithe.ini")
+
+		dir := copyTestFolder(t, map[string]string{
+			"regolithe.ini":       string(ini) + "\n[extension.orderingKey]\ntargets = model\n",
+			"_extensions.mapping": testExtensionMapping,
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "regolithe.ini: extension 'orderingKey' is already declared in _extensions.mapping")
+			})
+		})
+	})
+
+	Convey("Given I have a regolithe.ini declaring an invalid extension", t, func() {
+
+		ini, _ := os.ReadFile("./tests/regolithe.ini")
+
+		dir := copyTestFolder(t, map[string]string{
+			"regolithe.ini": string(ini) + "\n[extension.orderingKey]\ntargets = parameter\n",
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "regolithe.ini: schema error: orderingKey.targets.0: orderingKey.targets.0 must be one of the following")
+			})
+		})
+	})
+
+	Convey("Given I have a regolithe.ini redefining a built-in extension", t, func() {
+
+		ini, _ := os.ReadFile("./tests/regolithe.ini")
+
+		dir := copyTestFolder(t, map[string]string{
+			"regolithe.ini": string(ini) + "\n[extension.noLint]\ntargets = relation\n",
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "regolithe.ini: extension 'noLint' is built-in and cannot be redefined")
+			})
+		})
+	})
+}
@@ -0,0 +1,502 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+)
+
+// Timings of the locks of the cached repositories.
+const (
+	repositoryLockTimeout    = 10 * time.Minute
+	repositoryLockRetryDelay = 100 * time.Millisecond
+)
+
+// A GitSourceOption configures a Source returned by NewGitSource.
+type GitSourceOption func(*gitSource)
+
+// GitOptionAuth sets the authentication method to use.
+func GitOptionAuth(auth transport.AuthMethod) GitSourceOption {
+	return func(s *gitSource) {
+		s.auth = auth
+	}
+}
+
+// GitOptionToken uses the given token as HTTP bearer authentication.
+func GitOptionToken(token string) GitSourceOption {
+	return func(s *gitSource) {
+		s.auth = &http.BasicAuth{
+			Username: "Bearer",
+			Password: token,
+		}
+	}
+}
+
+// GitOptionSSHKey uses the given private key file for SSH authentication.
+// The passphrase is optional. The user is taken from the URL and defaults
+// to "git".
+func GitOptionSSHKey(path string, passphrase string) GitSourceOption {
+	return func(s *gitSource) {
+		s.sshKeyPath = path
+		s.sshKeyPassphrase = passphrase
+	}
+}
+
+// GitOptionCacheDir sets the folder where the repositories are cached,
+// keyed by URL. Cached repositories are only fetched when the requested
+// reference is not a cached tag or full commit hash. A cached repository
+// is locked while it is used, so the cache can be shared by concurrent
+// processes. If not set, the repository is fetched in a temporary folder.
+func GitOptionCacheDir(dir string) GitSourceOption {
+	return func(s *gitSource) {
+		s.cacheDir = dir
+	}
+}
+
+type gitSource struct {
+	url          string
+	ref          string
+	internalPath string
+
+	auth             transport.AuthMethod
+	sshKeyPath       string
+	sshKeyPassphrase string
+	cacheDir         string
+}
+
+// NewGitSource returns a Source reading the specifications from the given
+// internal path of the git repository at the given URL. The URL can use
+// any transport supported by git, like https, ssh or file. The reference
+// can be a branch, a tag or a commit hash, that can be abbreviated.
+// SSH URLs use the SSH agent unless a key is given using GitOptionSSHKey.
+func NewGitSource(url string, ref string, internalPath string, opts ...GitSourceOption) Source {
+
+	s := &gitSource{
+		url:          url,
+		ref:          ref,
+		internalPath: internalPath,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *gitSource) Fetch(ctx context.Context, opts FetchOptions) (*FetchResult, error) {
+
+	if s.ref == "" {
+		return nil, fmt.Errorf("unable to fetch '%s': a reference is required", s.url)
+	}
+
+	auth, err := s.authMethod()
+	if err != nil {
+		return nil, err
+	}
+
+	var folders []string
+	release := func() {
+		for _, f := range folders {
+			_ = os.RemoveAll(f) // nolint: errcheck
+		}
+	}
+
+	repoDir := s.cachedRepositoryDir()
+	if repoDir == "" {
+		if repoDir, err = os.MkdirTemp("", "regolithe-repo"); err != nil {
+			return nil, err
+		}
+		folders = append(folders, repoDir)
+	} else {
+		unlock, err := lockRepository(ctx, repoDir)
+		if err != nil {
+			return nil, err
+		}
+		// The cached repository is only needed until the
+		// commit is extracted, so it is unlocked on return.
+		defer unlock()
+	}
+
+	hash, err := s.resolve(ctx, repoDir, auth, opts)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	dest, err := os.MkdirTemp("", "regolithe-specs")
+	if err != nil {
+		release()
+		return nil, err
+	}
+	folders = append(folders, dest)
+
+	opts.progress("Extracting %s", hash)
+
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("unable to open repository in '%s': %w", repoDir, err)
+	}
+
+	if err := extractCommit(repo, hash, s.internalPath, dest); err != nil {
+		release()
+		return nil, err
+	}
+
+	return &FetchResult{
+		Path:    dest,
+		Commit:  hash.String(),
+		Release: release,
+	}, nil
+}
+
+// authMethod returns the transport.AuthMethod to use.
+func (s *gitSource) authMethod() (transport.AuthMethod, error) {
+
+	if s.sshKeyPath == "" {
+		return s.auth, nil
+	}
+
+	user := "git"
+	if ep, err := transport.NewEndpoint(s.url); err == nil && ep.User != "" {
+		user = ep.User
+	}
+
+	auth, err := ssh.NewPublicKeysFromFile(user, s.sshKeyPath, s.sshKeyPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load ssh key '%s': %w", s.sshKeyPath, err)
+	}
+
+	return auth, nil
+}
+
+// cachedRepositoryDir returns the folder of the cached
+// repository, or an empty string if there is no cache.
+func (s *gitSource) cachedRepositoryDir() string {
+
+	if s.cacheDir == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(s.url))
+
+	return filepath.Join(s.cacheDir, hex.EncodeToString(sum[:]))
+}
+
+// resolve returns the hash of the commit of the reference in the
+// repository stored in the given folder, fetching it if needed.
+func (s *gitSource) resolve(ctx context.Context, repoDir string, auth transport.AuthMethod, opts FetchOptions) (plumbing.Hash, error) {
+
+	repo, err := git.PlainOpen(repoDir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		repo, err = initRepository(repoDir, s.url)
+	}
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to open repository in '%s': %w", repoDir, err)
+	}
+
+	if hash, ok := cachedReference(repo, s.ref); ok {
+		opts.Logger.Debug("Using cached reference", "ref", s.ref, "repo", s.url, "commit", hash.String())
+		return hash, nil
+	}
+
+	opts.Logger.Info("Fetching repository", "ref", s.ref, "repo", s.url, "path", s.internalPath)
+	opts.progress("Fetching %s", s.url)
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RefSpecs: []config.RefSpec{
+			"+refs/heads/*:refs/heads/*",
+			"+refs/tags/*:refs/tags/*",
+		},
+		Auth:     auth,
+		Progress: opts.progressWriter(),
+		Tags:     git.AllTags,
+		Force:    true,
+	})
+
+	// The error returned by go-git when the context is canceled
+	// depends on the step of the fetch, so we report the context
+	// error instead.
+	if err != nil && ctx.Err() != nil {
+		return plumbing.ZeroHash, ctx.Err()
+	}
+
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return plumbing.ZeroHash, fmt.Errorf("unable to fetch '%s': %w", s.url, err)
+	}
+
+	return resolveReference(repo, s.ref, true)
+}
+
+// cachedReference returns the hash of the commit pointed by the given
+// reference if it can be resolved without fetching. Tags and commits are
+// not supposed to move, so there is no need to fetch them if they are
+// cached. Branches are always fetched, and so are abbreviated commit
+// hashes, as they could be the name of a branch that is not cached yet.
+func cachedReference(repo *git.Repository, ref string) (plumbing.Hash, bool) {
+
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName(ref), true); err == nil {
+		return plumbing.ZeroHash, false
+	}
+
+	_, err := repo.Reference(plumbing.NewTagReferenceName(ref), true)
+	if err != nil && len(ref) != 40 {
+		return plumbing.ZeroHash, false
+	}
+
+	hash, err := resolveReference(repo, ref, false)
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+
+	return hash, true
+}
+
+// lockRepository locks the cached repository in the given folder by
+// creating a lock file next to it, waiting for the lock to be released
+// if it is held by another process. Lock files older than
+// repositoryLockTimeout are considered stale and are removed. It returns
+// the function releasing the lock.
+func lockRepository(ctx context.Context, repoDir string) (func(), error) {
+
+	if err := os.MkdirAll(filepath.Dir(repoDir), 0750); err != nil {
+		return nil, err
+	}
+
+	lockPath := repoDir + ".lock"
+
+	for {
+
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_ = f.Close() // nolint: errcheck
+			return func() {
+				_ = os.Remove(lockPath) // nolint: errcheck
+			}, nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("unable to lock repository '%s': %w", repoDir, err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > repositoryLockTimeout {
+			_ = os.Remove(lockPath) // nolint: errcheck
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("unable to lock repository '%s': %w", repoDir, ctx.Err())
+		case <-time.After(repositoryLockRetryDelay):
+		}
+	}
+}
+
+// initRepository initializes a bare repository in the
+// given folder with the given URL as origin.
+func initRepository(dir string, url string) (*git.Repository, error) {
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+
+	repo, err := git.PlainInit(dir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repo.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{url},
+	}); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// resolveReference returns the hash of the commit pointed by the given
+// tag, commit hash or, if branches is true, branch. Branches are checked
+// first, then tags, then full references and finally commit hashes,
+// which can be abbreviated.
+func resolveReference(repo *git.Repository, ref string, branches bool) (plumbing.Hash, error) {
+
+	names := []plumbing.ReferenceName{plumbing.NewTagReferenceName(ref)}
+	if branches {
+		names = append([]plumbing.ReferenceName{plumbing.NewBranchReferenceName(ref)}, names...)
+		if strings.HasPrefix(ref, "refs/") {
+			names = append(names, plumbing.ReferenceName(ref))
+		}
+	}
+
+	for _, name := range names {
+
+		r, err := repo.Reference(name, true)
+		if err != nil {
+			continue
+		}
+
+		// Annotated tags point to a tag object.
+		if tag, err := repo.TagObject(r.Hash()); err == nil {
+			c, err := tag.Commit()
+			if err != nil {
+				return plumbing.ZeroHash, fmt.Errorf("unable to resolve tag '%s': %w", ref, err)
+			}
+			return c.Hash, nil
+		}
+
+		return r.Hash(), nil
+	}
+
+	if !isCommitHash(ref) {
+		return plumbing.ZeroHash, fmt.Errorf("unable to find reference '%s'", ref)
+	}
+
+	return resolveCommitHash(repo, ref)
+}
+
+// resolveCommitHash returns the hash of the only
+// commit whose hash starts with the given prefix.
+func resolveCommitHash(repo *git.Repository, prefix string) (plumbing.Hash, error) {
+
+	prefix = strings.ToLower(prefix)
+
+	if len(prefix) == 40 {
+		c, err := repo.CommitObject(plumbing.NewHash(prefix))
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("unable to find commit '%s'", prefix)
+		}
+		return c.Hash, nil
+	}
+
+	iter, err := repo.CommitObjects()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	defer iter.Close()
+
+	var matches []plumbing.Hash
+	if err := iter.ForEach(func(c *object.Commit) error {
+		if strings.HasPrefix(c.Hash.String(), prefix) {
+			matches = append(matches, c.Hash)
+		}
+		return nil
+	}); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	switch len(matches) {
+	case 0:
+		return plumbing.ZeroHash, fmt.Errorf("unable to find commit '%s'", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return plumbing.ZeroHash, fmt.Errorf("commit '%s' is ambiguous: %d commits match", prefix, len(matches))
+	}
+}
+
+// isCommitHash returns true if the given string
+// can be a commit hash, possibly abbreviated.
+func isCommitHash(s string) bool {
+
+	if len(s) < 4 || len(s) > 40 {
+		return false
+	}
+
+	_, err := hex.DecodeString(s + strings.Repeat("0", len(s)%2))
+
+	return err == nil
+}
+
+// ExtractRevision writes the files of the given internal path of the
+// given revision of the repository containing repoPath into dest. The
+// revision can be anything git understands, like a branch, a tag or an
+// abbreviated commit hash.
+func ExtractRevision(repoPath string, revision string, internalPath string, dest string) error {
+
+	repo, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return fmt.Errorf("unable to open repository: %w", err)
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+
+		// go-git does not resolve abbreviated commit hashes.
+		if !isCommitHash(revision) {
+			return err
+		}
+
+		h, herr := resolveCommitHash(repo, revision)
+		if herr != nil {
+			return herr
+		}
+
+		hash = &h
+	}
+
+	return extractCommit(repo, *hash, internalPath, dest)
+}
+
+// extractCommit writes the files of the given internal path
+// of the given commit of the repository into dest.
+func extractCommit(repo *git.Repository, hash plumbing.Hash, internalPath string, dest string) error {
+
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+
+	if p := strings.Trim(filepath.ToSlash(internalPath), "/"); p != "" && p != "." {
+		if tree, err = tree.Tree(p); err != nil {
+			return fmt.Errorf("unable to find path '%s': %w", internalPath, err)
+		}
+	}
+
+	return tree.Files().ForEach(func(f *object.File) error {
+
+		target := filepath.Join(dest, filepath.FromSlash(f.Name))
+
+		if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return err
+		}
+
+		content, err := f.Contents()
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(target, []byte(content), 0600)
+	})
+}
@@ -21,39 +21,178 @@ import (
 	"github.com/xeipuuv/gojsonschema"
 )
 
-// Pluralize pluralizes the given word.
+// pluralIrregulars holds the plurals that do not follow the rules.
+// Common singulars ending with "us" are listed too, so their plurals
+// are known and left untouched, like "statuses".
+var pluralIrregulars = map[string]string{
+	"apparatus":  "apparatuses",
+	"bonus":      "bonuses",
+	"bus":        "buses",
+	"campus":     "campuses",
+	"census":     "censuses",
+	"child":      "children",
+	"consensus":  "consensuses",
+	"criterion":  "criteria",
+	"focus":      "focuses",
+	"foot":       "feet",
+	"goose":      "geese",
+	"half":       "halves",
+	"knife":      "knives",
+	"leaf":       "leaves",
+	"life":       "lives",
+	"man":        "men",
+	"matrix":     "matrices",
+	"mouse":      "mice",
+	"nexus":      "nexuses",
+	"ox":         "oxen",
+	"person":     "people",
+	"prospectus": "prospectuses",
+	"quiz":       "quizzes",
+	"self":       "selves",
+	"shelf":      "shelves",
+	"status":     "statuses",
+	"syllabus":   "syllabuses",
+	"tooth":      "teeth",
+	"vertex":     "vertices",
+	"virus":      "viruses",
+	"wife":       "wives",
+	"wolf":       "wolves",
+	"woman":      "women",
+}
+
+// pluralUncountables holds the words that have no plural form.
+var pluralUncountables = map[string]struct{}{
+	"data":        {},
+	"deer":        {},
+	"equipment":   {},
+	"feedback":    {},
+	"fish":        {},
+	"hardware":    {},
+	"information": {},
+	"metadata":    {},
+	"news":        {},
+	"series":      {},
+	"sheep":       {},
+	"software":    {},
+	"species":     {},
+}
+
+// pluralIrregularPlurals holds the plurals of pluralIrregulars
+// so already pluralized words are left untouched.
+var pluralIrregularPlurals = func() map[string]struct{} {
+
+	out := make(map[string]struct{}, len(pluralIrregulars))
+	for _, plural := range pluralIrregulars {
+		out[plural] = struct{}{}
+	}
+
+	return out
+}()
+
+// Pluralize pluralizes the given word. When the word is
+// camel cased, only the last word is pluralized. Words are
+// returned unchanged only if they are known plurals or have
+// no plural form. Acronyms are pluralized with a lowercase s,
+// like APIs.
 func Pluralize(word string) string {
 
 	if len(word) == 0 {
 		return word
 	}
 
-	lastChar := word[len(word)-1:]
-	if lastChar == "s" {
+	start := lastWordIndex(word)
+	prefix, last := word[:start], word[start:]
+	lower := strings.ToLower(last)
+
+	if _, ok := pluralUncountables[lower]; ok {
 		return word
 	}
 
-	if len(word) >= 2 {
-		secondLastChar := word[len(word)-2 : len(word)-1]
+	if _, ok := pluralIrregularPlurals[lower]; ok {
+		return word
+	}
 
-		if lastChar == "y" &&
-			secondLastChar != "a" &&
-			secondLastChar != "e" &&
-			secondLastChar != "i" &&
-			secondLastChar != "o" &&
-			secondLastChar != "u" &&
-			secondLastChar != "y" {
-			return word[:len(word)-1] + "ies"
+	if plural, ok := pluralIrregulars[lower]; ok {
+		switch {
+		case last == strings.ToUpper(last) && len(last) > 1:
+			plural = strings.ToUpper(plural)
+		case isUpper(last[0]):
+			plural = strings.ToUpper(plural[:1]) + plural[1:]
 		}
+		return prefix + plural
+	}
 
-		if lastChar == "h" && secondLastChar == "c" {
-			word += "e"
+	if isAcronym(strings.TrimSuffix(last, "s")) {
+		if last[len(last)-1] == 's' || last[len(last)-1] == 'S' {
+			return word
 		}
+		return word + "s"
+	}
+
+	switch {
+	case strings.HasSuffix(lower, "sis"), strings.HasSuffix(lower, "xis"):
+		return word[:len(word)-2] + "es"
+
+	case strings.HasSuffix(lower, "s"),
+		strings.HasSuffix(lower, "sh"),
+		strings.HasSuffix(lower, "ch"),
+		strings.HasSuffix(lower, "x"),
+		strings.HasSuffix(lower, "z"):
+		return word + "es"
+
+	case len(lower) >= 2 && lower[len(lower)-1] == 'y' && !strings.ContainsRune("aeiouy", rune(lower[len(lower)-2])):
+		return word[:len(word)-1] + "ies"
 	}
 
 	return word + "s"
 }
 
+// lastWordIndex returns the index of the last camel cased
+// word of the given word. A word starts at an uppercase letter
+// following a lowercase one, or at the last letter of an
+// uppercase run followed by a lowercase one, like Person in
+// APIPerson. The plural s of an acronym does not start a word.
+func lastWordIndex(word string) int {
+
+	for i := len(word) - 1; i > 0; i-- {
+
+		if !isUpper(word[i]) {
+			continue
+		}
+
+		if !isUpper(word[i-1]) {
+			return i
+		}
+
+		if i+1 < len(word) && !isUpper(word[i+1]) && word[i+1:] != "s" {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// isAcronym returns true if the given word has
+// at least two letters, all of them uppercase.
+func isAcronym(word string) bool {
+
+	if len(word) < 2 {
+		return false
+	}
+
+	for i := 0; i < len(word); i++ {
+		if !isUpper(word[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
 func makeSchemaValidationError(message string, res []gojsonschema.ResultError) []error {
 
 	out := make([]error, len(res))
@@ -112,6 +251,20 @@ func sortAttributes(attrs []*Attribute) {
 	})
 }
 
+// withProperties returns the given attributes followed
+// by their nested properties, recursively.
+func withProperties(attrs []*Attribute) []*Attribute {
+
+	out := make([]*Attribute, 0, len(attrs))
+
+	for _, attr := range attrs {
+		out = append(out, attr)
+		out = append(out, withProperties(attr.Properties)...)
+	}
+
+	return out
+}
+
 func sortParameters(params []*Parameter) {
 
 	sort.Slice(params, func(i int, j int) bool {
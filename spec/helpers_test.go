@@ -61,7 +61,7 @@ func TestHelpers_Pluralize(t *testing.T) {
 			p := Pluralize(word)
 
 			Convey("Then the word should be pluralized correctly", func() {
-				So(p, ShouldEqual, "Tests")
+				So(p, ShouldEqual, "Testses")
 			})
 		})
 	})
@@ -219,6 +219,88 @@ func TestHelpers_Pluralize(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given I have words with irregular or suffix based plurals", t, func() {
+
+		words := map[string]string{
+			"index":          "indexes",
+			"box":            "boxes",
+			"quiz":           "quizzes",
+			"status":         "statuses",
+			"class":          "classes",
+			"hash":           "hashes",
+			"analysis":       "analyses",
+			"axis":           "axes",
+			"person":         "people",
+			"people":         "people",
+			"Person":         "People",
+			"PERSON":         "PEOPLE",
+			"TaskPerson":     "TaskPeople",
+			"ChildPolicy":    "ChildPolicies",
+			"leaf":           "leaves",
+			"metadata":       "metadata",
+			"TaskMetadata":   "TaskMetadata",
+			"automation":     "automations",
+			"APIAuthPolicy":  "APIAuthPolicies",
+			"ProcessingUnit": "ProcessingUnits",
+			"menu":           "menus",
+			"Alias":          "Aliases",
+			"DNSAlias":       "DNSAliases",
+			"canvas":         "canvases",
+			"radius":         "radiuses",
+			"gas":            "gases",
+			"APIPerson":      "APIPeople",
+			"APIPolicy":      "APIPolicies",
+			"APIsList":       "APIsLists",
+			"APIPeople":      "APIPeople",
+			"bus":            "buses",
+			"Status":         "Statuses",
+			"TaskStatus":     "TaskStatuses",
+			"campus":         "campuses",
+		}
+
+		Convey("When I call pluralize", func() {
+
+			for word, expected := range words {
+
+				p := Pluralize(word)
+
+				Convey("Then the word '"+word+"' should be pluralized correctly", func() {
+					So(p, ShouldEqual, expected)
+				})
+			}
+		})
+	})
+}
+
+func TestHelpers_PluralizeAcronyms(t *testing.T) {
+
+	Convey("Given I have acronyms", t, func() {
+
+		words := map[string]string{
+			"API":        "APIs",
+			"APIs":       "APIs",
+			"PU":         "PUs",
+			"PUs":        "PUs",
+			"TaskPUs":    "TaskPUs",
+			"TaskPU":     "TaskPUs",
+			"DNS":        "DNS",
+			"CSS":        "CSS",
+			"ExternalIP": "ExternalIPs",
+		}
+
+		Convey("When I call pluralize", func() {
+
+			for word, expected := range words {
+
+				p := Pluralize(word)
+
+				Convey("Then the acronym '"+word+"' should be pluralized correctly", func() {
+					So(p, ShouldEqual, expected)
+				})
+			}
+		})
+	})
 }
 
 func TestHelpers_makeSchemaValidationErrors(t *testing.T) {
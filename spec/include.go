@@ -0,0 +1,243 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// includeKey is the key of the items of the attribute and relation
+// lists including the items listed in a fragment file.
+const includeKey = "include"
+
+// A listInclude is an include item of a list.
+type listInclude struct {
+	file string
+
+	// position is the number of items declared
+	// before the include item in the list.
+	position int
+}
+
+// extractIncludes removes the include items from the attribute and
+// relation lists of the given spec data and records them in the
+// specification. The data is returned as is if there is no include.
+func (s *specification) extractIncludes(data []byte) ([]byte, error) {
+
+	s.attributeIncludes = nil
+	s.relationIncludes = nil
+
+	if !hasIncludes(data) {
+		return data, nil
+	}
+
+	var raw yaml.MapSlice
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		// The strict decoding of the specification reports the error.
+		return data, nil
+	}
+
+	var found bool
+
+	for i, item := range raw {
+
+		switch item.Key {
+
+		case rootAttributesKey:
+
+			versions, ok := item.Value.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+
+			for j, v := range versions {
+
+				list, ok := v.Value.([]any)
+				if !ok {
+					continue
+				}
+
+				items, includes, err := splitIncludes(list)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(includes) == 0 {
+					continue
+				}
+
+				if s.attributeIncludes == nil {
+					s.attributeIncludes = map[string][]listInclude{}
+				}
+
+				s.attributeIncludes[fmt.Sprintf("%v", v.Key)] = includes
+				versions[j].Value = items
+				found = true
+			}
+
+		case rootRelationsKey:
+
+			list, ok := item.Value.([]any)
+			if !ok {
+				continue
+			}
+
+			items, includes, err := splitIncludes(list)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(includes) == 0 {
+				continue
+			}
+
+			s.relationIncludes = includes
+			raw[i].Value = items
+			found = true
+		}
+	}
+
+	if !found {
+		return data, nil
+	}
+
+	return yaml.Marshal(raw)
+}
+
+// splitIncludes returns the items of the given list
+// that are not include items and the include items.
+func splitIncludes(list []any) (items []any, includes []listInclude, err error) {
+
+	items = []any{}
+
+	for _, item := range list {
+
+		if m, ok := item.(yaml.MapSlice); ok && len(m) == 1 && m[0].Key == includeKey {
+			if file, ok := m[0].Value.(string); ok {
+				if err := validateIncludePath(file); err != nil {
+					return nil, nil, err
+				}
+				includes = append(includes, listInclude{file: file, position: len(items)})
+				continue
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, includes, nil
+}
+
+// validateIncludePath checks the given included file is inside the
+// folder of the specification, so it is vendored with it.
+func validateIncludePath(file string) error {
+
+	clean := filepath.ToSlash(filepath.Clean(filepath.FromSlash(file)))
+
+	if filepath.IsAbs(file) || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("included file '%s' must be inside the folder of the specification", file)
+	}
+
+	return nil
+}
+
+// resolveIncludes inserts the items listed in the included files
+// in the attributes and the relations, where the include items were
+// declared. The files are resolved relative to the folder of the
+// specification, which they must be in.
+func (s *specification) resolveIncludes() error {
+
+	dir := filepath.Dir(s.path)
+
+	for version, includes := range s.attributeIncludes {
+
+		// Includes are inserted backward so the
+		// positions of the next ones stay correct.
+		for i := len(includes) - 1; i >= 0; i-- {
+
+			var attrs []*Attribute
+			if err := decodeIncludedFile(filepath.Join(dir, includes[i].file), &attrs); err != nil {
+				return err
+			}
+
+			for _, attr := range attrs {
+				attr.includedFrom = includes[i].file
+			}
+
+			s.RawAttributes[version] = slices.Insert(s.RawAttributes[version], includes[i].position, attrs...)
+		}
+	}
+
+	for i := len(s.relationIncludes) - 1; i >= 0; i-- {
+
+		var rels []*Relation
+		if err := decodeIncludedFile(filepath.Join(dir, s.relationIncludes[i].file), &rels); err != nil {
+			return err
+		}
+
+		for _, rel := range rels {
+			rel.includedFrom = s.relationIncludes[i].file
+		}
+
+		s.RawRelations = slices.Insert(s.RawRelations, s.relationIncludes[i].position, rels...)
+	}
+
+	return nil
+}
+
+// decodeIncludedFile strictly decodes the list
+// contained in the given file into out.
+func decodeIncludedFile(file string, out any) error {
+
+	data, err := os.ReadFile(file) // #nosec
+	if err != nil {
+		return fmt.Errorf("unable to read included file: %w", err)
+	}
+
+	if err := yaml.UnmarshalStrict(data, out); err != nil {
+		return fmt.Errorf("unable to decode included file '%s': %s", filepath.Base(file), err)
+	}
+
+	return nil
+}
+
+// withIncludeItems returns the given items with the given include
+// items inserted at their position if keepPositions is true, or
+// first otherwise.
+func withIncludeItems(items []yaml.MapSlice, includes []listInclude, keepPositions bool) []yaml.MapSlice {
+
+	for i := len(includes) - 1; i >= 0; i-- {
+
+		var position int
+		if keepPositions {
+			position = min(includes[i].position, len(items))
+		}
+
+		items = slices.Insert(items, position, yaml.MapSlice{{Key: includeKey, Value: includes[i].file}})
+	}
+
+	return items
+}
+
+// hasIncludes returns true if the given data contains an include item.
+// It is a cheap check avoiding to decode the specifications twice.
+func hasIncludes(data []byte) bool {
+	return bytes.Contains(data, []byte(includeKey+":"))
+}
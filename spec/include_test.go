@@ -0,0 +1,202 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const includeTestAttributes = `- name: priority
+  description: The priority of the task.
+  type: integer
+  exposed: true
+  stored: true
+
+- name: tags
+  description: The tags of the task.
+  type: list
+  subtype: string
+  exposed: true
+  stored: true
+`
+
+const includeTestRelations = `- rest_name: task
+  get:
+    description: Retrieves the tasks of the root.
+`
+
+func TestInclude_LoadSpecificationSet(t *testing.T) {
+
+	task, _ := os.ReadFile("./tests/task.spec")
+	root, _ := os.ReadFile("./tests/root.spec")
+
+	Convey("Given I have a spec folder with includes", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"task.spec":            strings.Replace(string(task), "  v1:\n", "  v1:\n  - include: task.attributes.yaml\n", 1),
+			"root.spec":            strings.Replace(string(root), "relations:\n", "relations:\n- include: root.relations.yaml\n", 1),
+			"task.attributes.yaml": includeTestAttributes,
+			"root.relations.yaml":  includeTestRelations,
+		})
+
+		Convey("When I load it", func() {
+
+			set, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the included attributes should be loaded", func() {
+				s := set.Specification("task")
+				So(s.Attribute("priority", "v1"), ShouldNotBeNil)
+				So(s.Attribute("tags", "v1"), ShouldNotBeNil)
+				So(s.Attribute("name", "v1"), ShouldNotBeNil)
+			})
+
+			Convey("Then the included relations should be loaded", func() {
+				s := set.Specification("root")
+				So(len(s.Relations()), ShouldEqual, 3)
+				So(s.Relation("task"), ShouldNotBeNil)
+				So(s.Relation("task").Specification(), ShouldEqual, set.Specification("task"))
+			})
+		})
+
+		Convey("When I load the task and write it back", func() {
+
+			s, err := LoadSpecification(filepath.Join(dir, "task.spec"), true)
+			So(err, ShouldBeNil)
+
+			buf := &bytes.Buffer{}
+			So(s.Write(buf), ShouldBeNil)
+
+			Convey("Then the include should be kept", func() {
+				So(buf.String(), ShouldContainSubstring, "  v1:\n  - include: task.attributes.yaml\n\n  - name: description\n")
+				So(buf.String(), ShouldNotContainSubstring, "name: priority")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with a missing include", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"task.spec": strings.Replace(string(task), "  v1:\n", "  v1:\n  - include: nope.yaml\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "unable to read included file: open ")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with includes outside of it", t, func() {
+
+		for file, expected := range map[string]string{
+			"../attributes.yaml":             "included file '../attributes.yaml' must be inside the folder of the specification",
+			"fragments/../../other.yaml":     "included file 'fragments/../../other.yaml' must be inside the folder of the specification",
+			"/etc/regolithe/attributes.yaml": "included file '/etc/regolithe/attributes.yaml' must be inside the folder of the specification",
+		} {
+
+			dir := copyTestFolder(t, map[string]string{
+				"task.spec": strings.Replace(string(task), "  v1:\n", "  v1:\n  - include: "+file+"\n", 1),
+			})
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then loading a spec including '"+file+"' should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, expected)
+			})
+		}
+	})
+
+	Convey("Given I have a spec folder with an invalid include", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"task.spec": strings.Replace(string(task), "  v1:\n", "  v1:\n  - include: bad.yaml\n", 1),
+			"bad.yaml":  "- name: bad\n  nope: true\n",
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "unable to decode included file 'bad.yaml': yaml: unmarshal errors:\n  line 2: field nope not found in type spec.Attribute")
+			})
+		})
+	})
+}
+
+func TestInclude_Format(t *testing.T) {
+
+	Convey("Given I read a spec with includes without a path", t, func() {
+
+		data := `# Model
+model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  package: todo-list
+  group: core
+  description: Represent a task.
+
+# Attributes
+attributes:
+  v1:
+  - include: task.attributes.yaml
+
+  - name: name
+    description: The name.
+    type: string
+    exposed: true
+
+# Relations
+relations:
+- include: task.relations.yaml
+`
+
+		s := NewSpecification()
+		err := s.Read(strings.NewReader(data), true)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then only the local attributes should be loaded", func() {
+			So(len(s.Attributes("v1")), ShouldEqual, 1)
+			So(s.Relations(), ShouldBeEmpty)
+		})
+
+		Convey("When I write it back", func() {
+
+			buf := &bytes.Buffer{}
+			So(s.Write(buf), ShouldBeNil)
+
+			Convey("Then the layout should be kept", func() {
+				So(buf.String(), ShouldEqual, data)
+			})
+		})
+	})
+}
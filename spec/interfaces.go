@@ -53,6 +53,34 @@ type SpecificationSet interface {
 	Groups() []string
 }
 
+// A DefinitionProvider is a SpecificationSet giving access to the
+// shared definitions its specifications are built from. The
+// SpecificationSets returned by this package implement it.
+type DefinitionProvider interface {
+
+	// ParameterMapping returns the specification set global ParameterMapping.
+	ParameterMapping() ParameterMapping
+
+	// EnumMapping returns the specification set named enums.
+	EnumMapping() EnumMapping
+
+	// Abstracts returns the abstract specifications indexed by name.
+	Abstracts() map[string]Specification
+
+	// ExtensionMapping returns the specification set ExtensionMapping.
+	// It always contains the built-in extensions.
+	ExtensionMapping() ExtensionMapping
+}
+
+// A CommitProvider is a SpecificationSet knowing the commit it has been
+// loaded from. The SpecificationSets returned by this package implement it.
+type CommitProvider interface {
+
+	// Commit returns the hash of the commit the specifications were
+	// loaded from, or an empty string if they were not loaded from git.
+	Commit() string
+}
+
 // A Specification is the interface representing a Regolithe Specification.
 type Specification interface {
 
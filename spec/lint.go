@@ -0,0 +1,387 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LintSeverity represents the severity of a LintRule.
+type LintSeverity string
+
+// Various values for LintSeverity.
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityOff     LintSeverity = "off"
+)
+
+// Identifiers of the built-in lint rules.
+const (
+	LintRuleModelDescriptionPeriod       = "model-description-period"
+	LintRuleAttributeDescriptionPeriod   = "attribute-description-period"
+	LintRuleAttributeRequiredExample     = "attribute-required-example"
+	LintRuleAttributeAllowedCharsMessage = "attribute-allowed-chars-message"
+	LintRuleRelationDescription          = "relation-description"
+	LintRuleRelationDescriptionPeriod    = "relation-description-period"
+	LintRuleParameterDescriptionPeriod   = "parameter-description-period"
+	LintRuleParameterExampleValue        = "parameter-example-value"
+	LintRulePublicConsistency            = "public-consistency"
+)
+
+// lintExtensionKey is the extension key used in models and attributes
+// to hold the list of lint rule identifiers to suppress.
+const lintExtensionKey = "noLint"
+
+// A LintRule represents a style check that can be configured
+// in the [lint] section of the regolithe.ini file, using the
+// rule ID as key and the LintSeverity as value.
+type LintRule struct {
+	ID          string
+	Description string
+	Severity    LintSeverity
+
+	// Check is an optional function run over the loaded SpecificationSet.
+	// Most built-in rules are checked during the validation of the
+	// specifications and do not define it.
+	Check func(set SpecificationSet) []error
+}
+
+var (
+	lintRules     = map[string]*LintRule{}
+	lintRulesLock sync.RWMutex
+)
+
+func init() {
+
+	for _, r := range []*LintRule{
+		{
+			ID:          LintRuleModelDescriptionPeriod,
+			Description: "The description of a model must end with a period.",
+			Severity:    LintSeverityError,
+		},
+		{
+			ID:          LintRuleAttributeDescriptionPeriod,
+			Description: "The description of an attribute must end with a period.",
+			Severity:    LintSeverityError,
+		},
+		{
+			ID:          LintRuleAttributeRequiredExample,
+			Description: "A required attribute must define a default_value or an example_value.",
+			Severity:    LintSeverityError,
+		},
+		{
+			ID:          LintRuleAttributeAllowedCharsMessage,
+			Description: "An attribute defining allowed_chars must define allowed_chars_message.",
+			Severity:    LintSeverityError,
+		},
+		{
+			ID:          LintRuleRelationDescription,
+			Description: "A relation action must have a description.",
+			Severity:    LintSeverityError,
+		},
+		{
+			ID:          LintRuleRelationDescriptionPeriod,
+			Description: "The description of a relation action must end with a period.",
+			Severity:    LintSeverityError,
+		},
+		{
+			ID:          LintRuleParameterDescriptionPeriod,
+			Description: "The description of a parameter must end with a period.",
+			Severity:    LintSeverityError,
+		},
+		{
+			ID:          LintRuleParameterExampleValue,
+			Description: "A string parameter without default_value must define an example_value.",
+			Severity:    LintSeverityError,
+		},
+		{
+			ID:          LintRulePublicConsistency,
+			Description: "A specification of the documentation or of the public JSON schema must not reference a specification that is private or without exposed attributes in it.",
+			Severity:    LintSeverityWarning,
+			Check:       CheckPublicConsistency,
+		},
+	} {
+		if err := RegisterLintRule(r); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterLintRule registers the given LintRule. It returns an error
+// if a rule with the same ID is already registered.
+func RegisterLintRule(rule *LintRule) error {
+
+	if rule.ID == "" {
+		return errors.New("lint rule must have an ID")
+	}
+
+	if err := rule.Severity.validate(); err != nil {
+		return fmt.Errorf("lint rule '%s': %w", rule.ID, err)
+	}
+
+	lintRulesLock.Lock()
+	defer lintRulesLock.Unlock()
+
+	if _, ok := lintRules[rule.ID]; ok {
+		return fmt.Errorf("lint rule '%s' is already registered", rule.ID)
+	}
+
+	lintRules[rule.ID] = rule
+
+	return nil
+}
+
+// LintRules returns all the registered LintRules sorted by ID.
+func LintRules() []*LintRule {
+
+	lintRulesLock.RLock()
+	defer lintRulesLock.RUnlock()
+
+	out := make([]*LintRule, 0, len(lintRules))
+	for _, r := range lintRules {
+		out = append(out, r)
+	}
+
+	sort.Slice(out, func(i int, j int) bool {
+		return strings.Compare(out[i].ID, out[j].ID) == -1
+	})
+
+	return out
+}
+
+func lintRule(id string) *LintRule {
+
+	lintRulesLock.RLock()
+	defer lintRulesLock.RUnlock()
+
+	return lintRules[id]
+}
+
+func (s LintSeverity) validate() error {
+
+	switch s {
+	case LintSeverityError, LintSeverityWarning, LintSeverityOff:
+		return nil
+	default:
+		return fmt.Errorf("invalid severity '%s': must be 'error', 'warning' or 'off'", s)
+	}
+}
+
+// A LintError is an error reported by a LintRule.
+type LintError struct {
+	RuleID    string
+	Severity  LintSeverity
+	Model     *Model
+	Attribute *Attribute
+
+	err error
+}
+
+// NewLintError returns a new LintError for the rule with the given ID.
+// The model and the attribute are optional and are used to look up
+// suppressions declared in their extensions.
+func NewLintError(ruleID string, model *Model, attribute *Attribute, err error) *LintError {
+
+	severity := LintSeverityError
+	if r := lintRule(ruleID); r != nil {
+		severity = r.Severity
+	}
+
+	return &LintError{
+		RuleID:    ruleID,
+		Severity:  severity,
+		Model:     model,
+		Attribute: attribute,
+		err:       err,
+	}
+}
+
+// Error implements the error interface.
+func (e *LintError) Error() string {
+	return e.err.Error()
+}
+
+// model returns the model the issue is about, which is
+// the one of its attribute if it has no model, if any.
+func (e *LintError) model() *Model {
+
+	if e.Model != nil {
+		return e.Model
+	}
+
+	if e.Attribute != nil && e.Attribute.linkedSpecification != nil {
+		return e.Attribute.linkedSpecification.Model()
+	}
+
+	return nil
+}
+
+// Unwrap returns the underlying error.
+func (e *LintError) Unwrap() error {
+	return e.err
+}
+
+// suppressed returns true if the rule is suppressed
+// by the extensions of the model or the attribute.
+func (e *LintError) suppressed() bool {
+
+	if e.Attribute != nil && suppresses(e.Attribute.Extensions, e.RuleID) {
+		return true
+	}
+
+	model := e.Model
+	if model == nil && e.Attribute != nil && e.Attribute.linkedSpecification != nil {
+		model = e.Attribute.linkedSpecification.Model()
+	}
+
+	return model != nil && suppresses(model.Extensions, e.RuleID)
+}
+
+func suppresses(extensions map[string]any, ruleID string) bool {
+
+	list, ok := extensions[lintExtensionKey].([]any)
+	if !ok {
+		return false
+	}
+
+	for _, item := range list {
+		if s, ok := item.(string); ok && (s == ruleID || s == "all") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lint applies the given configuration to the given validation errors.
+// It returns the errors that must be treated as fatal and the warnings.
+// Suppressed and disabled issues are dropped.
+func lint(config *Config, errs []error) (fatal []error, warnings []*LintError) {
+
+	for _, err := range errs {
+
+		var lerr *LintError
+		if !errors.As(err, &lerr) {
+			fatal = append(fatal, err)
+			continue
+		}
+
+		if config != nil {
+			lerr.Severity = config.LintSeverity(lerr.RuleID, lerr.Severity)
+		}
+
+		if lerr.Severity == LintSeverityOff || lerr.suppressed() {
+			continue
+		}
+
+		if lerr.Severity == LintSeverityWarning {
+			warnings = append(warnings, lerr)
+			continue
+		}
+
+		fatal = append(fatal, lerr)
+	}
+
+	return fatal, warnings
+}
+
+// checkLintRules runs the Check function of the registered rules
+// over the given SpecificationSet.
+func checkLintRules(set SpecificationSet) []error {
+
+	var errs []error
+
+	for _, r := range LintRules() {
+
+		if r.Check == nil {
+			continue
+		}
+
+		for _, err := range r.Check(set) {
+
+			var lerr *LintError
+			if errors.As(err, &lerr) {
+				errs = append(errs, lerr)
+				continue
+			}
+
+			errs = append(errs, NewLintError(r.ID, nil, nil, err))
+		}
+	}
+
+	return errs
+}
+
+// LintSpecificationSet loads the specification set in the given folder
+// and returns all the issues it contains, sorted by message. Issues
+// that are not reported by a LintRule are returned as LintErrors with
+// an empty RuleID and a LintSeverityError severity.
+// The given LoadOptions can be used to run additional ValidationRules.
+// With OptionSelect, the issues of the specifications that are not
+// selected are left out.
+func LintSpecificationSet(dirname string, opts ...LoadOption) ([]*LintError, error) {
+
+	cfg := newLoadConfig(opts...)
+
+	selector, err := ParseSelector(cfg.selector)
+	if err != nil {
+		return nil, err
+	}
+
+	set, errs, err := loadSpecificationSet(dirname, cfg.nameConvertFunc, cfg.typeConvertFunc, cfg.mappingMode, cfg.overlays)
+	if err != nil {
+		return nil, err
+	}
+
+	errs = append(errs, checkLintRules(set)...)
+	errs = append(errs, checkValidationRules(set, cfg.rules)...)
+
+	fatal, warnings := lint(set.configuration, errs)
+
+	issues := make([]*LintError, 0, len(fatal)+len(warnings))
+
+	for _, err := range fatal {
+
+		var lerr *LintError
+		if errors.As(err, &lerr) {
+			issues = append(issues, lerr)
+			continue
+		}
+
+		issues = append(issues, &LintError{Severity: LintSeverityError, err: err})
+	}
+
+	issues = append(issues, warnings...)
+
+	if cfg.selector != "" {
+		issues = slices.DeleteFunc(issues, func(issue *LintError) bool {
+			model := issue.model()
+			if model == nil {
+				return false
+			}
+			s := set.Specification(model.RestName)
+			return s != nil && !s.Model().IsRoot && !selector(s)
+		})
+	}
+
+	sort.SliceStable(issues, func(i int, j int) bool {
+		return strings.Compare(issues[i].Error(), issues[j].Error()) == -1
+	})
+
+	return issues, nil
+}
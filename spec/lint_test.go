@@ -0,0 +1,312 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// copyTestFolder copies the files of the ./tests folder in a
+// temporary folder, applies the given modifications and returns
+// the path of the temporary folder. A modification with empty
+// content removes the file.
+func copyTestFolder(t *testing.T, modifications map[string]string) string {
+
+	dir := t.TempDir()
+
+	entries, err := os.ReadDir("./tests")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(path.Join("./tests", e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path.Join(dir, e.Name()), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, content := range modifications {
+
+		if content == "" {
+			if err := os.Remove(path.Join(dir, name)); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		if err := os.WriteFile(path.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+const lintTestSpec = `model:
+  rest_name: thing
+  resource_name: things
+  entity_name: Thing
+  package: todo-list
+  group: core
+  description: A thing
+  get:
+    description: Gets the thing.
+  extensions:
+    noLint:
+    - relation-description-period
+
+attributes:
+  v1:
+  - name: name
+    description: The name
+    type: string
+    exposed: true
+  - name: other
+    description: The other
+    type: string
+    exposed: true
+    extensions:
+      noLint:
+      - attribute-description-period
+
+relations:
+- rest_name: task
+  get:
+    description: Gets the tasks
+`
+
+func TestLint_RegisterLintRule(t *testing.T) {
+
+	Convey("Given I register a rule with no ID", t, func() {
+
+		err := RegisterLintRule(&LintRule{Severity: LintSeverityError})
+
+		Convey("Then err should not be nil", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "lint rule must have an ID")
+		})
+	})
+
+	Convey("Given I register a rule with an invalid severity", t, func() {
+
+		err := RegisterLintRule(&LintRule{ID: "test-invalid", Severity: "nope"})
+
+		Convey("Then err should not be nil", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "lint rule 'test-invalid': invalid severity 'nope': must be 'error', 'warning' or 'off'")
+		})
+	})
+
+	Convey("Given I register a rule that already exists", t, func() {
+
+		err := RegisterLintRule(&LintRule{ID: LintRuleModelDescriptionPeriod, Severity: LintSeverityError})
+
+		Convey("Then err should not be nil", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "lint rule 'model-description-period' is already registered")
+		})
+	})
+
+	Convey("Given I retrieve the rules", t, func() {
+
+		rules := LintRules()
+
+		Convey("Then the built-in rules should be present and sorted", func() {
+			So(len(rules), ShouldBeGreaterThanOrEqualTo, 8)
+			for i := 1; i < len(rules); i++ {
+				So(rules[i-1].ID, ShouldBeLessThan, rules[i].ID)
+			}
+		})
+	})
+}
+
+func TestLint_LintError(t *testing.T) {
+
+	Convey("Given I have a lint error", t, func() {
+
+		underlying := errors.New("oops")
+		err := NewLintError(LintRuleModelDescriptionPeriod, nil, nil, underlying)
+
+		Convey("Then it should be correctly initialized", func() {
+			So(err.Error(), ShouldEqual, "oops")
+			So(err.RuleID, ShouldEqual, LintRuleModelDescriptionPeriod)
+			So(err.Severity, ShouldEqual, LintSeverityError)
+			So(errors.Is(err, underlying), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given I have a lint error suppressed by the attribute", t, func() {
+
+		err := NewLintError(LintRuleAttributeDescriptionPeriod, nil, &Attribute{
+			Extensions: map[string]any{
+				"noLint": []any{LintRuleAttributeDescriptionPeriod},
+			},
+		}, errors.New("oops"))
+
+		Convey("Then it should be suppressed", func() {
+			So(err.suppressed(), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given I have a lint error suppressed by the model of the attribute", t, func() {
+
+		err := NewLintError(LintRuleAttributeDescriptionPeriod, nil, &Attribute{
+			linkedSpecification: &specification{
+				RawModel: &Model{
+					Extensions: map[string]any{
+						"noLint": []any{"all"},
+					},
+				},
+			},
+		}, errors.New("oops"))
+
+		Convey("Then it should be suppressed", func() {
+			So(err.suppressed(), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given I have a lint error that is not suppressed", t, func() {
+
+		err := NewLintError(LintRuleModelDescriptionPeriod, &Model{
+			Extensions: map[string]any{
+				"noLint": []any{LintRuleAttributeDescriptionPeriod},
+			},
+		}, nil, errors.New("oops"))
+
+		Convey("Then it should not be suppressed", func() {
+			So(err.suppressed(), ShouldBeFalse)
+		})
+	})
+}
+
+func TestLint_LintSpecificationSet(t *testing.T) {
+
+	Convey("Given I have a spec folder with style issues", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"thing.spec": lintTestSpec,
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should contain the non suppressed issues", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, strings.Join([]string{
+					"thing.spec: description of attribute 'name' must end with a period",
+					"thing.spec: model description must end with a period",
+				}, "\n"))
+			})
+		})
+
+		Convey("When I lint it", func() {
+
+			issues, err := LintSpecificationSet(dir)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the issues should be correct", func() {
+				So(len(issues), ShouldEqual, 2)
+				So(issues[0].RuleID, ShouldEqual, LintRuleAttributeDescriptionPeriod)
+				So(issues[0].Severity, ShouldEqual, LintSeverityError)
+				So(issues[1].RuleID, ShouldEqual, LintRuleModelDescriptionPeriod)
+				So(issues[1].Severity, ShouldEqual, LintSeverityError)
+			})
+		})
+
+		Convey("When I lint it with a selector", func() {
+
+			issues, err := LintSpecificationSet(dir, OptionSelect("rest_name!=thing"))
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the issues of the specifications that are not selected should be left out", func() {
+				So(issues, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with style issues relaxed in the configuration", t, func() {
+
+		ini, _ := os.ReadFile("./tests/regolithe.ini")
+
+		dir := copyTestFolder(t, map[string]string{
+			"thing.spec":     lintTestSpec,
+			"regolithe.ini":  string(ini) + "\n[lint]\nmodel-description-period = warning\nattribute-description-period = off\n",
+			"_unused.ignore": "nothing",
+		})
+
+		Convey("When I load it", func() {
+
+			set, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+				So(set.Specification("thing"), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When I lint it", func() {
+
+			issues, err := LintSpecificationSet(dir)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then only the warning should be reported", func() {
+				So(len(issues), ShouldEqual, 1)
+				So(issues[0].RuleID, ShouldEqual, LintRuleModelDescriptionPeriod)
+				So(issues[0].Severity, ShouldEqual, LintSeverityWarning)
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with an invalid lint configuration", t, func() {
+
+		ini, _ := os.ReadFile("./tests/regolithe.ini")
+
+		dir := copyTestFolder(t, map[string]string{
+			"regolithe.ini": string(ini) + "\n[lint]\nmodel-description-period = maybe\n",
+		})
+
+		Convey("When I lint it", func() {
+
+			_, err := LintSpecificationSet(dir)
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "invalid lint configuration for rule 'model-description-period': invalid severity 'maybe': must be 'error', 'warning' or 'off'")
+			})
+		})
+	})
+}
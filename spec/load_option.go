@@ -0,0 +1,149 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"context"
+	"log/slog"
+)
+
+// A ValidationRule is a function run over a resolved SpecificationSet.
+// The errors it returns are merged into the validation errors of the
+// set. Returning LintErrors allows the issues to be configured from
+// the [lint] section of the regolithe.ini file and to be suppressed
+// using the noLint extension.
+type ValidationRule func(set SpecificationSet) []error
+
+// A LoadOption configures the loading of a SpecificationSet.
+type LoadOption func(*loadConfig)
+
+type loadConfig struct {
+	nameConvertFunc AttributeNameConverterFunc
+	typeConvertFunc AttributeTypeConverterFunc
+	mappingMode     string
+	overlays        []string
+	selector        string
+	strict          bool
+	rules           []ValidationRule
+	logger          *slog.Logger
+	progress        func(string)
+}
+
+func newLoadConfig(opts ...LoadOption) *loadConfig {
+
+	cfg := &loadConfig{
+		logger: slog.New(discardHandler{}),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// OptionNameConverter sets the function used to
+// compute the converted names of the attributes.
+func OptionNameConverter(f AttributeNameConverterFunc) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.nameConvertFunc = f
+	}
+}
+
+// OptionTypeConverter sets the function used to
+// compute the converted types of the attributes.
+func OptionTypeConverter(f AttributeTypeConverterFunc) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.typeConvertFunc = f
+	}
+}
+
+// OptionMappingMode sets the mode used to resolve the
+// type and validation mappings, like "elemental".
+func OptionMappingMode(mode string) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.mappingMode = mode
+	}
+}
+
+// OptionOverlays sets the overlay folders merged, in order, into the
+// specifications before they are resolved. An overlay folder contains
+// partial .spec and .abs files named after the files they patch.
+func OptionOverlays(dirs ...string) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.overlays = append(cfg.overlays, dirs...)
+	}
+}
+
+// OptionSelect only keeps the specifications selected by the given
+// selector expression, as described in ParseSelector. The specifications
+// are validated before they are selected.
+func OptionSelect(expr string) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.selector = expr
+	}
+}
+
+// OptionStrict makes the lint warnings fatal. By default,
+// they are only logged.
+func OptionStrict(strict bool) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.strict = strict
+	}
+}
+
+// OptionLogger sets the logger used while loading the
+// specifications. By default, nothing is logged.
+func OptionLogger(logger *slog.Logger) LoadOption {
+	return func(cfg *loadConfig) {
+		if logger != nil {
+			cfg.logger = logger
+		}
+	}
+}
+
+// OptionProgress sets a function called with the progress
+// messages of the Source, like the ones of a git clone.
+func OptionProgress(f func(message string)) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.progress = f
+	}
+}
+
+// OptionValidationRules adds the given ValidationRules
+// to the ones run after the set is loaded.
+func OptionValidationRules(rules ...ValidationRule) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.rules = append(cfg.rules, rules...)
+	}
+}
+
+// discardHandler is a slog.Handler discarding all records.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// checkValidationRules runs the given rules over the given SpecificationSet.
+func checkValidationRules(set SpecificationSet, rules []ValidationRule) []error {
+
+	var errs []error
+
+	for _, rule := range rules {
+		if rule != nil {
+			errs = append(errs, rule(set)...)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,192 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoadOption_OptionValidationRules(t *testing.T) {
+
+	// filterableRule reports the stored attributes that are not filterable.
+	filterableRule := func(set SpecificationSet) []error {
+
+		var errs []error
+		for _, s := range set.Specifications() {
+			for _, attr := range s.Attributes("") {
+				if attr.Stored && !attr.Filterable {
+					errs = append(errs, NewLintError(
+						"stored-filterable",
+						s.Model(),
+						attr,
+						fmt.Errorf("%s.spec: stored attribute '%s' must be filterable", s.Model().RestName, attr.Name),
+					))
+				}
+			}
+		}
+
+		return errs
+	}
+
+	publicRule := func(set SpecificationSet) []error {
+		return []error{fmt.Errorf("%d specifications checked", set.Len())}
+	}
+
+	Convey("Given I load a spec folder with validation rules", t, func() {
+
+		var called SpecificationSet
+		spyRule := func(set SpecificationSet) []error {
+			called = set
+			return nil
+		}
+
+		set, err := LoadSpecificationSet("./tests", nil, nil, "", OptionValidationRules(spyRule, nil))
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the rule should have been called with the resolved set", func() {
+			So(called, ShouldEqual, set)
+		})
+	})
+
+	Convey("Given I load a spec folder with failing validation rules", t, func() {
+
+		_, err := LoadSpecificationSet(
+			"./tests",
+			nil,
+			nil,
+			"",
+			OptionValidationRules(filterableRule),
+			OptionValidationRules(publicRule),
+		)
+
+		Convey("Then err should contain the errors of the rules", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, strings.Join([]string{
+				"4 specifications checked",
+				"user.spec: stored attribute 'archived' must be filterable",
+			}, "\n"))
+		})
+	})
+
+	Convey("Given I have a spec folder disabling a custom rule in the configuration", t, func() {
+
+		ini, _ := os.ReadFile("./tests/regolithe.ini")
+
+		dir := copyTestFolder(t, map[string]string{
+			"regolithe.ini": string(ini) + "\n[lint]\nstored-filterable = warning\n",
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "", OptionValidationRules(filterableRule))
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When I lint it", func() {
+
+			issues, err := LintSpecificationSet(dir, OptionValidationRules(filterableRule))
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the issues should be reported as warnings", func() {
+				So(len(issues), ShouldEqual, 1)
+				So(issues[0].RuleID, ShouldEqual, "stored-filterable")
+				So(issues[0].Severity, ShouldEqual, LintSeverityWarning)
+			})
+		})
+	})
+}
+
+func TestLoadOption_Load(t *testing.T) {
+
+	ini, _ := os.ReadFile("./tests/regolithe.ini")
+
+	dir := copyTestFolder(t, map[string]string{
+		"thing.spec":    lintTestSpec,
+		"regolithe.ini": string(ini) + "\n[lint]\nmodel-description-period = warning\nattribute-description-period = off\n",
+	})
+
+	Convey("Given I load a spec folder with warnings", t, func() {
+
+		buf := &bytes.Buffer{}
+		logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+
+		set, err := Load(context.Background(), NewDirectorySource(dir), OptionLogger(logger))
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+			So(set.Specification("thing"), ShouldNotBeNil)
+		})
+
+		Convey("Then the warnings should be logged", func() {
+			So(buf.String(), ShouldEqual, "level=WARN msg=\"thing.spec: model description must end with a period\" rule=model-description-period\n")
+		})
+	})
+
+	Convey("Given I load a spec folder with warnings in strict mode", t, func() {
+
+		_, err := Load(context.Background(), NewDirectorySource(dir), OptionStrict(true))
+
+		Convey("Then err should not be nil", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "thing.spec: model description must end with a period")
+		})
+	})
+
+	Convey("Given I load a spec folder with a mapping mode", t, func() {
+
+		set, err := Load(context.Background(), NewDirectorySource("./tests"), OptionMappingMode("test"))
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the validations should be resolved for the mode", func() {
+			So(set.Specification("user").Model().ValidationProviders, ShouldNotBeEmpty)
+		})
+	})
+
+	Convey("Given I load a spec folder with a canceled context", t, func() {
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Load(ctx, NewDirectorySource("./tests"))
+
+		Convey("Then err should be correct", func() {
+			So(err, ShouldEqual, context.Canceled)
+		})
+	})
+}
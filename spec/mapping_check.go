@@ -0,0 +1,150 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MappingKind represents the kind of mapping checked by CheckMappings.
+type MappingKind string
+
+// Various values for MappingKind.
+const (
+	MappingKindType       MappingKind = "type"
+	MappingKindValidation MappingKind = "validation"
+)
+
+// MappingStatus represents the status of a mapping entry for a mode.
+type MappingStatus string
+
+// Various values for MappingStatus.
+const (
+	// MappingStatusMissing means the mapping has no entry for the mode.
+	MappingStatusMissing MappingStatus = "missing"
+
+	// MappingStatusIgnored means the mapping declares the mode with
+	// an empty value, meaning it is intentionally not mapped.
+	MappingStatusIgnored MappingStatus = "ignored"
+)
+
+// A MappingIssue describes an external subtype or a validation
+// used in a SpecificationSet that has no mapping for a mode.
+type MappingIssue struct {
+	Kind   MappingKind
+	Name   string
+	Mode   string
+	Status MappingStatus
+
+	// UsedBy contains the sorted list of users of the mapping,
+	// like "task.name" for an attribute or "task" for a model.
+	UsedBy []string
+}
+
+// String returns the string representation of the MappingIssue.
+func (i *MappingIssue) String() string {
+	return fmt.Sprintf("%s '%s' is %s for mode '%s' (used by %s)", i.Kind, i.Name, i.Status, i.Mode, strings.Join(i.UsedBy, ", "))
+}
+
+// CheckMappings returns the issues of the type and validation mappings
+// of the given SpecificationSet for the given mode. Type issues come first,
+// then validation issues, each sorted by name.
+// An external subtype or a validation that has no entry for the mode is
+// reported as missing. One that declares the mode with an empty value,
+// like "mode: ~", is reported as ignored.
+func CheckMappings(set SpecificationSet, mode string) []*MappingIssue {
+
+	types := map[string]map[string]struct{}{}
+	validations := map[string]map[string]struct{}{}
+
+	use := func(index map[string]map[string]struct{}, name string, user string) {
+		if _, ok := index[name]; !ok {
+			index[name] = map[string]struct{}{}
+		}
+		index[name][user] = struct{}{}
+	}
+
+	useValidations := func(names []string, user string) {
+		for _, v := range names {
+			if name, _, err := ParseValidationCall(v); err == nil {
+				use(validations, name, user)
+			}
+		}
+	}
+
+	for _, s := range set.Specifications() {
+
+		model := s.Model()
+		useValidations(model.Validations, model.RestName)
+
+		for _, version := range s.AttributeVersions() {
+
+			for _, attr := range withProperties(s.Attributes(version)) {
+
+				user := model.RestName + "." + attr.qualifiedName()
+
+				if attr.Type == AttributeTypeExt {
+					use(types, attr.SubType, user)
+				}
+
+				useValidations(attr.Validations, user)
+			}
+		}
+	}
+
+	var issues []*MappingIssue
+
+	for _, name := range sortedKeys(types) {
+
+		tmap, ok := set.TypeMapping()[name][mode]
+		if status, report := mappingStatus(ok, tmap == nil); report {
+			issues = append(issues, &MappingIssue{
+				Kind:   MappingKindType,
+				Name:   name,
+				Mode:   mode,
+				Status: status,
+				UsedBy: sortedKeys(types[name]),
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(validations) {
+
+		vmap, ok := set.ValidationMapping()[name][mode]
+		if status, report := mappingStatus(ok, vmap == nil); report {
+			issues = append(issues, &MappingIssue{
+				Kind:   MappingKindValidation,
+				Name:   name,
+				Mode:   mode,
+				Status: status,
+				UsedBy: sortedKeys(validations[name]),
+			})
+		}
+	}
+
+	return issues
+}
+
+// mappingStatus returns the status of a mapping entry and
+// whether it should be reported.
+func mappingStatus(exists bool, empty bool) (MappingStatus, bool) {
+
+	switch {
+	case !exists:
+		return MappingStatusMissing, true
+	case empty:
+		return MappingStatusIgnored, true
+	default:
+		return "", false
+	}
+}
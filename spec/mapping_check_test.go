@@ -0,0 +1,187 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMappingCheck_CheckMappings(t *testing.T) {
+
+	Convey("Given I have the test specification set", t, func() {
+
+		set, err := LoadSpecificationSet("./tests", nil, nil, "")
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("When I check the mappings for mode test", func() {
+
+			issues := CheckMappings(set, "test")
+
+			Convey("Then there should be no issue", func() {
+				So(issues, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When I check the mappings for mode other", func() {
+
+			issues := CheckMappings(set, "other")
+
+			Convey("Then the issues should be correct", func() {
+				So(len(issues), ShouldEqual, 2)
+				So(issues[0].String(), ShouldEqual, "validation '$nospace' is missing for mode 'other' (used by task.name)")
+				So(issues[1].String(), ShouldEqual, "validation '$username' is missing for mode 'other' (used by user)")
+			})
+		})
+	})
+
+	Convey("Given I have a set with missing and ignored mappings", t, func() {
+
+		set := makeTestSet(
+			`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+  validations:
+  - $check(1)
+
+attributes:
+  v1:
+  - name: a
+    description: A.
+    type: external
+    subtype: known
+    validations:
+    - $ignored
+  - name: b
+    description: B.
+    type: external
+    subtype: unknown
+  - name: c
+    description: C.
+    type: external
+    subtype: skipped
+    validations:
+    - $check(2)
+`,
+		).(*specificationSet)
+
+		set.typeMap = NewTypeMapping()
+		So(set.typeMap.Read(strings.NewReader(`known:
+  go:
+    type: Known
+
+skipped:
+  go: ~
+`), true), ShouldBeNil)
+
+		set.validationsMap = NewValidationMapping()
+		So(set.validationsMap.Read(strings.NewReader(`$ignored:
+  go: ~
+
+$check:
+  other:
+    name: check
+`), true), ShouldBeNil)
+
+		Convey("When I check the mappings for mode go", func() {
+
+			issues := CheckMappings(set, "go")
+
+			Convey("Then the issues should be correct", func() {
+				So(len(issues), ShouldEqual, 4)
+
+				So(issues[0].Kind, ShouldEqual, MappingKindType)
+				So(issues[0].Name, ShouldEqual, "skipped")
+				So(issues[0].Status, ShouldEqual, MappingStatusIgnored)
+				So(issues[0].UsedBy, ShouldResemble, []string{"task.c"})
+
+				So(issues[1].Kind, ShouldEqual, MappingKindType)
+				So(issues[1].Name, ShouldEqual, "unknown")
+				So(issues[1].Status, ShouldEqual, MappingStatusMissing)
+				So(issues[1].UsedBy, ShouldResemble, []string{"task.b"})
+
+				So(issues[2].Kind, ShouldEqual, MappingKindValidation)
+				So(issues[2].Name, ShouldEqual, "$check")
+				So(issues[2].Status, ShouldEqual, MappingStatusMissing)
+				So(issues[2].UsedBy, ShouldResemble, []string{"task", "task.c"})
+
+				So(issues[3].Kind, ShouldEqual, MappingKindValidation)
+				So(issues[3].Name, ShouldEqual, "$ignored")
+				So(issues[3].Status, ShouldEqual, MappingStatusIgnored)
+				So(issues[3].UsedBy, ShouldResemble, []string{"task.a"})
+			})
+		})
+
+		Convey("When I check the mappings of nested properties for mode go", func() {
+
+			set := makeTestSet(
+				`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+
+attributes:
+  v1:
+  - name: address
+    description: The address.
+    type: object
+    properties:
+    - name: location
+      description: The location.
+      type: external
+      subtype: unknown
+      validations:
+      - $ignored
+`,
+			).(*specificationSet)
+			set.typeMap = NewTypeMapping()
+			set.validationsMap = NewValidationMapping()
+			So(set.validationsMap.Read(strings.NewReader("$ignored:\n  go: ~\n"), true), ShouldBeNil)
+
+			issues := CheckMappings(set, "go")
+
+			Convey("Then the issues should be correct", func() {
+				So(len(issues), ShouldEqual, 2)
+				So(issues[0].String(), ShouldEqual, "type 'unknown' is missing for mode 'go' (used by task.address.location)")
+				So(issues[1].String(), ShouldEqual, "validation '$ignored' is ignored for mode 'go' (used by task.address.location)")
+			})
+		})
+
+		Convey("When I call All on the type mapping for mode go", func() {
+
+			m := set.typeMap.All("go")
+
+			Convey("Then the ignored types should be skipped", func() {
+				So(len(m), ShouldEqual, 1)
+				So(m[0].Type, ShouldEqual, "Known")
+			})
+		})
+
+		Convey("When I call Mapping on an ignored type", func() {
+
+			m, err := set.typeMap.Mapping("go", "skipped")
+
+			Convey("Then the mapping should be nil", func() {
+				So(err, ShouldBeNil)
+				So(m, ShouldBeNil)
+			})
+		})
+	})
+}
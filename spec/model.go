@@ -19,25 +19,30 @@ type Model struct {
 	// NOTE: Order of attributes matters!
 	// The YAML will be dumped respecting this order.
 
-	RestName      string          `yaml:"rest_name,omitempty"       json:"rest_name,omitempty"`
-	ResourceName  string          `yaml:"resource_name,omitempty"   json:"resource_name,omitempty"`
-	EntityName    string          `yaml:"entity_name,omitempty"     json:"entity_name,omitempty"`
-	Package       string          `yaml:"package,omitempty"         json:"package,omitempty"`
-	Group         string          `yaml:"group,omitempty"           json:"group,omitempty"`
-	Description   string          `yaml:"description,omitempty"     json:"description,omitempty"`
-	Documentation string          `yaml:"documentation,omitempty"   json:"documentation,omitempty"`
-	Aliases       []string        `yaml:"aliases,omitempty"         json:"aliases,omitempty"`
-	Private       bool            `yaml:"private,omitempty"         json:"private,omitempty"`
-	Get           *RelationAction `yaml:"get,omitempty"             json:"get,omitempty"`
-	Update        *RelationAction `yaml:"update,omitempty"          json:"update,omitempty"`
-	Delete        *RelationAction `yaml:"delete,omitempty"          json:"delete,omitempty"`
-	Extends       []string        `yaml:"extends,omitempty"         json:"extends,omitempty"`
-	IsRoot        bool            `yaml:"root,omitempty"            json:"root,omitempty"`
-	Detached      bool            `yaml:"detached,omitempty"        json:"detached,omitempty"`
-	Validations   []string        `yaml:"validations,omitempty"     json:"validations,omitempty"`
-	Extensions    map[string]any  `yaml:"extensions,omitempty"      json:"extensions,omitempty"`
+	RestName         string          `yaml:"rest_name,omitempty"          json:"rest_name,omitempty"`
+	ResourceName     string          `yaml:"resource_name,omitempty"      json:"resource_name,omitempty"`
+	EntityName       string          `yaml:"entity_name,omitempty"        json:"entity_name,omitempty"`
+	EntityNamePlural string          `yaml:"entity_name_plural,omitempty" json:"entity_name_plural,omitempty"`
+	Package          string          `yaml:"package,omitempty"            json:"package,omitempty"`
+	Group            string          `yaml:"group,omitempty"              json:"group,omitempty"`
+	Description      string          `yaml:"description,omitempty"        json:"description,omitempty"`
+	Documentation    string          `yaml:"documentation,omitempty"      json:"documentation,omitempty"`
+	Aliases          []string        `yaml:"aliases,omitempty"            json:"aliases,omitempty"`
+	Private          bool            `yaml:"private,omitempty"            json:"private,omitempty"`
+	Tags             []string        `yaml:"tags,omitempty"               json:"tags,omitempty"`
+	Get              *RelationAction `yaml:"get,omitempty"                json:"get,omitempty"`
+	Update           *RelationAction `yaml:"update,omitempty"             json:"update,omitempty"`
+	Delete           *RelationAction `yaml:"delete,omitempty"             json:"delete,omitempty"`
+	Extends          []string        `yaml:"extends,omitempty"            json:"extends,omitempty"`
+	IsRoot           bool            `yaml:"root,omitempty"               json:"root,omitempty"`
+	Detached         bool            `yaml:"detached,omitempty"           json:"detached,omitempty"`
+	PreserveOrder    bool            `yaml:"preserve_order,omitempty"     json:"preserve_order,omitempty"`
+	Validations      []string        `yaml:"validations,omitempty"        json:"validations,omitempty"`
+	Extensions       map[string]any  `yaml:"extensions,omitempty"         json:"extensions,omitempty"`
 
-	EntityNamePlural string `yaml:"-" json:"-"`
+	// ValidationProviders holds the resolved validations, keyed by
+	// function name followed by the arguments, if any.
+	ValidationProviders map[string]*ValidationMap `yaml:"-" json:"-"`
 }
 
 // Validate validates the Model.
@@ -46,7 +51,7 @@ func (m *Model) Validate() []error {
 	var errs []error
 
 	if m.Description != "" && m.Description[len(m.Description)-1] != '.' {
-		errs = append(errs, fmt.Errorf("%s.spec: model description must end with a period", m.RestName))
+		errs = append(errs, NewLintError(LintRuleModelDescriptionPeriod, m, nil, fmt.Errorf("%s.spec: model description must end with a period", m.RestName)))
 	}
 
 	if m.Get != nil {
@@ -0,0 +1,297 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Identifiers of the naming lint rules.
+const (
+	LintRuleNamingRestNameLowercase      = "naming-rest-name-lowercase"
+	LintRuleNamingResourceNamePlural     = "naming-resource-name-plural"
+	LintRuleNamingEntityNamePascalCase   = "naming-entity-name-pascal-case"
+	LintRuleNamingCollision              = "naming-collision"
+	LintRuleNamingAttributeNameCollision = "naming-attribute-name-collision"
+)
+
+func init() {
+
+	for _, r := range []*LintRule{
+		{
+			ID:          LintRuleNamingRestNameLowercase,
+			Description: "The rest_name of a model must be lowercase.",
+			Severity:    LintSeverityWarning,
+			Check:       checkRestNameLowercase,
+		},
+		{
+			ID:          LintRuleNamingResourceNamePlural,
+			Description: "The resource_name of a non root model must be the plural of its rest_name.",
+			Severity:    LintSeverityWarning,
+			Check:       checkResourceNamePlural,
+		},
+		{
+			ID:          LintRuleNamingEntityNamePascalCase,
+			Description: "The entity_name of a model must be PascalCase.",
+			Severity:    LintSeverityWarning,
+			Check:       checkEntityNamePascalCase,
+		},
+		{
+			ID:          LintRuleNamingCollision,
+			Description: "The rest_name, resource_name, entity_name and aliases of a model must be unique in the set.",
+			Severity:    LintSeverityError,
+			Check:       checkNameCollisions,
+		},
+		{
+			ID:          LintRuleNamingAttributeNameCollision,
+			Description: "The attribute names of a model must be unique after conversion.",
+			Severity:    LintSeverityError,
+			Check:       checkAttributeNameCollisions,
+		},
+	} {
+		if err := RegisterLintRule(r); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func checkRestNameLowercase(set SpecificationSet) []error {
+
+	var errs []error
+
+	for _, s := range set.Specifications() {
+
+		m := s.Model()
+		if m.RestName != strings.ToLower(m.RestName) {
+			errs = append(errs, NewLintError(LintRuleNamingRestNameLowercase, m, nil, fmt.Errorf("%s.spec: rest_name '%s' must be lowercase", m.RestName, m.RestName)))
+		}
+	}
+
+	return errs
+}
+
+func checkResourceNamePlural(set SpecificationSet) []error {
+
+	var errs []error
+
+	for _, s := range set.Specifications() {
+
+		m := s.Model()
+		if m.IsRoot {
+			continue
+		}
+
+		if expected := expectedResourceName(m); m.ResourceName != expected {
+			errs = append(errs, NewLintError(LintRuleNamingResourceNamePlural, m, nil, fmt.Errorf("%s.spec: resource_name '%s' should be '%s'", m.RestName, m.ResourceName, expected)))
+		}
+	}
+
+	return errs
+}
+
+// expectedResourceName returns the plural of the rest_name of the given
+// model. An explicit entity_name_plural is used when the rest_name is
+// the lowercased entity_name.
+func expectedResourceName(m *Model) string {
+
+	explicit := m.EntityNamePlural != "" && m.EntityNamePlural != Pluralize(m.EntityName)
+
+	if explicit && strings.ToLower(m.EntityName) == m.RestName {
+		return strings.ToLower(m.EntityNamePlural)
+	}
+
+	return Pluralize(m.RestName)
+}
+
+func checkEntityNamePascalCase(set SpecificationSet) []error {
+
+	var errs []error
+
+	for _, s := range set.Specifications() {
+
+		m := s.Model()
+		if !isPascalCase(m.EntityName) {
+			errs = append(errs, NewLintError(LintRuleNamingEntityNamePascalCase, m, nil, fmt.Errorf("%s.spec: entity_name '%s' must be PascalCase", m.RestName, m.EntityName)))
+		}
+	}
+
+	return errs
+}
+
+func checkNameCollisions(set SpecificationSet) []error {
+
+	var errs []error
+
+	restNames := map[string][]*Model{}
+	resourceNames := map[string][]*Model{}
+	entityNames := map[string][]*Model{}
+	aliases := map[string][]*Model{}
+
+	for _, s := range set.Specifications() {
+
+		m := s.Model()
+
+		restNames[m.RestName] = append(restNames[m.RestName], m)
+		resourceNames[m.ResourceName] = append(resourceNames[m.ResourceName], m)
+		entityNames[m.EntityName] = append(entityNames[m.EntityName], m)
+
+		declared := map[string]struct{}{}
+		for _, alias := range m.Aliases {
+
+			if _, ok := declared[alias]; ok {
+				errs = append(errs, NewLintError(LintRuleNamingCollision, m, nil, fmt.Errorf("%s.spec: alias '%s' is declared more than once", m.RestName, alias)))
+				continue
+			}
+			declared[alias] = struct{}{}
+
+			aliases[alias] = append(aliases[alias], m)
+		}
+	}
+
+	for _, index := range []struct {
+		kind  string
+		names map[string][]*Model
+	}{
+		{"rest_name", restNames},
+		{"resource_name", resourceNames},
+		{"entity_name", entityNames},
+		{"alias", aliases},
+	} {
+		for _, name := range sortedCollisions(index.names) {
+
+			models := index.names[name]
+			for _, m := range models {
+				errs = append(errs, NewLintError(LintRuleNamingCollision, m, nil, fmt.Errorf("%s.spec: %s '%s' is also used by %s", m.RestName, index.kind, name, otherModels(models, m))))
+			}
+		}
+	}
+
+	// An alias must not shadow the rest_name or resource_name of another model.
+	for _, alias := range sortedKeys(aliases) {
+
+		for _, m := range aliases[alias] {
+
+			for _, names := range []map[string][]*Model{restNames, resourceNames} {
+
+				others := otherModels(names[alias], m)
+				if others == "" {
+					continue
+				}
+
+				errs = append(errs, NewLintError(LintRuleNamingCollision, m, nil, fmt.Errorf("%s.spec: alias '%s' collides with the name of %s", m.RestName, alias, others)))
+			}
+		}
+	}
+
+	return errs
+}
+
+func checkAttributeNameCollisions(set SpecificationSet) []error {
+
+	var errs []error
+
+	for _, s := range set.Specifications() {
+
+		for _, version := range sortVersionStrings(s.AttributeVersions()) {
+
+			converted := map[string][]*Attribute{}
+
+			for _, attr := range s.Attributes(version) {
+
+				name := attr.ConvertedName
+				if name == "" {
+					name = attr.Name
+				}
+
+				converted[name] = append(converted[name], attr)
+			}
+
+			for _, name := range sortedKeys(converted) {
+
+				attrs := converted[name]
+				if len(attrs) < 2 {
+					continue
+				}
+
+				names := make([]string, len(attrs))
+				for i, attr := range attrs {
+					names[i] = fmt.Sprintf("'%s'", attr.Name)
+				}
+
+				errs = append(errs, NewLintError(LintRuleNamingAttributeNameCollision, s.Model(), nil, fmt.Errorf("%s.spec: attributes %s of version %s all convert to '%s'", s.Model().RestName, strings.Join(names, ", "), version, name)))
+			}
+		}
+	}
+
+	return errs
+}
+
+// isPascalCase returns true if the given name starts
+// with an upper case letter and only contains letters
+// and digits.
+func isPascalCase(name string) bool {
+
+	if name == "" || name[0] < 'A' || name[0] > 'Z' {
+		return false
+	}
+
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortedCollisions returns the sorted names that are used by more than one model.
+func sortedCollisions(names map[string][]*Model) []string {
+
+	var out []string
+	for name, models := range names {
+		if name != "" && len(models) > 1 {
+			out = append(out, name)
+		}
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+// otherModels returns the quoted rest names of the given
+// models, except the given one, joined by commas.
+func otherModels(models []*Model, except *Model) string {
+
+	var names []string
+	for _, m := range models {
+		if m != except {
+			names = append(names, fmt.Sprintf("'%s'", m.RestName))
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,236 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func errorStrings(errs []error) []string {
+
+	out := make([]string, len(errs))
+	for i, err := range errs {
+		out[i] = err.Error()
+	}
+
+	return out
+}
+
+func TestNaming_Conventions(t *testing.T) {
+
+	Convey("Given I have the test specification set", t, func() {
+
+		issues, err := LintSpecificationSet("./tests")
+
+		Convey("Then it should not have any naming issue", func() {
+			So(err, ShouldBeNil)
+			So(issues, ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given I have a set with explicit entity_name_plural", t, func() {
+
+		set := makeTestSet(
+			`model:
+  rest_name: cactus
+  resource_name: cacti
+  entity_name: Cactus
+  entity_name_plural: Cacti
+  description: A cactus.
+`,
+			`model:
+  rest_name: fungus
+  resource_name: funguses
+  entity_name: Fungus
+  entity_name_plural: Fungi
+  description: A fungus.
+`,
+		)
+
+		Convey("When I check the resource names", func() {
+
+			errs := checkResourceNamePlural(set)
+
+			Convey("Then the explicit plural should be expected", func() {
+				So(errorStrings(errs), ShouldResemble, []string{
+					"fungus.spec: resource_name 'funguses' should be 'fungi'",
+				})
+			})
+		})
+	})
+
+	Convey("Given I have a set with badly named specifications", t, func() {
+
+		set := makeTestSet(
+			`model:
+  rest_name: Policy
+  resource_name: policys
+  entity_name: Policy
+  description: A policy.
+`,
+			`model:
+  rest_name: task
+  resource_name: task
+  entity_name: my_task
+  description: A task.
+`,
+			`model:
+  rest_name: root
+  resource_name: root
+  entity_name: Root
+  description: The root.
+  root: true
+`,
+		)
+
+		Convey("When I check the rest names", func() {
+
+			errs := checkRestNameLowercase(set)
+
+			Convey("Then the errors should be correct", func() {
+				So(errorStrings(errs), ShouldResemble, []string{
+					"Policy.spec: rest_name 'Policy' must be lowercase",
+				})
+			})
+		})
+
+		Convey("When I check the resource names", func() {
+
+			errs := checkResourceNamePlural(set)
+
+			Convey("Then the errors should be correct", func() {
+				So(errorStrings(errs), ShouldResemble, []string{
+					"Policy.spec: resource_name 'policys' should be 'Policies'",
+					"task.spec: resource_name 'task' should be 'tasks'",
+				})
+			})
+		})
+
+		Convey("When I check the entity names", func() {
+
+			errs := checkEntityNamePascalCase(set)
+
+			Convey("Then the errors should be correct", func() {
+				So(errorStrings(errs), ShouldResemble, []string{
+					"task.spec: entity_name 'my_task' must be PascalCase",
+				})
+				So(errs[0].(*LintError).Severity, ShouldEqual, LintSeverityWarning)
+			})
+		})
+	})
+}
+
+func TestNaming_Collisions(t *testing.T) {
+
+	Convey("Given I have a set with colliding names", t, func() {
+
+		set := makeTestSet(
+			`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+  aliases:
+  - t
+  - user
+`,
+			`model:
+  rest_name: todo
+  resource_name: tasks
+  entity_name: Task
+  description: A todo.
+  aliases:
+  - t
+`,
+			`model:
+  rest_name: user
+  resource_name: users
+  entity_name: User
+  description: A user.
+  aliases:
+  - u
+  - u
+`,
+		)
+
+		Convey("When I check the collisions", func() {
+
+			errs := checkNameCollisions(set)
+
+			Convey("Then the errors should be correct", func() {
+				So(errorStrings(errs), ShouldResemble, []string{
+					"user.spec: alias 'u' is declared more than once",
+					"task.spec: resource_name 'tasks' is also used by 'todo'",
+					"todo.spec: resource_name 'tasks' is also used by 'task'",
+					"task.spec: entity_name 'Task' is also used by 'todo'",
+					"todo.spec: entity_name 'Task' is also used by 'task'",
+					"task.spec: alias 't' is also used by 'todo'",
+					"todo.spec: alias 't' is also used by 'task'",
+					"task.spec: alias 'user' collides with the name of 'user'",
+				})
+				So(errs[0].(*LintError).Severity, ShouldEqual, LintSeverityError)
+			})
+		})
+	})
+
+	Convey("Given I have a set with attributes colliding after conversion", t, func() {
+
+		set := makeTestSet(
+			`model:
+  rest_name: task
+  resource_name: tasks
+  entity_name: Task
+  description: A task.
+
+attributes:
+  v1:
+  - name: userID
+    description: The user ID.
+    type: string
+  - name: userid
+    description: The user id.
+    type: string
+  - name: name
+    description: The name.
+    type: string
+`,
+		)
+
+		for _, attr := range set.Specification("task").Attributes("v1") {
+			attr.ConvertedName = strings.ToUpper(attr.Name)
+		}
+
+		Convey("When I check the collisions", func() {
+
+			errs := checkAttributeNameCollisions(set)
+
+			Convey("Then the errors should be correct", func() {
+				So(errorStrings(errs), ShouldResemble, []string{
+					"task.spec: attributes 'userID', 'userid' of version v1 all convert to 'USERID'",
+				})
+			})
+		})
+	})
+
+	Convey("Given I load a set with attributes colliding after conversion", t, func() {
+
+		_, err := LoadSpecificationSet("./tests", func(string) string { return "same" }, nil, "")
+
+		Convey("Then err should not be nil", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "task.spec: attributes 'ID', 'description', 'name', 'parentID', 'parentType', 'status' of version v1 all convert to 'same'")
+		})
+	})
+}
@@ -0,0 +1,267 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// overlayPatchKey is the key of a list item of an overlay that
+// can be set to overlayPatchDelete to remove the matching item.
+const (
+	overlayPatchKey    = "$patch"
+	overlayPatchDelete = "delete"
+)
+
+// overlayListKeys are the keys identifying the items of the
+// lists that are merged by overlays, like the attributes by
+// name and the relations by rest_name.
+var overlayListKeys = []string{"name", "rest_name"}
+
+// checkOverlays verifies the .spec and .abs files of the given overlay
+// folders patch files existing in the given specification folder.
+func checkOverlays(dirname string, overlays []string) error {
+
+	for _, dir := range overlays {
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("unable to read overlay: %w", err)
+		}
+
+		for _, e := range entries {
+
+			if e.IsDir() || (path.Ext(e.Name()) != ".spec" && path.Ext(e.Name()) != ".abs") {
+				continue
+			}
+
+			if _, err := os.Stat(filepath.Join(dirname, e.Name())); err != nil {
+				return fmt.Errorf("overlay %s: %s: no such file in the specification set", dir, e.Name())
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadOverlaidSpecification loads the specification at the given path
+// merged with the file with the same name in each of the given overlay
+// folders, in order.
+//
+// Overlays are merged like this:
+//   - Maps are merged recursively. A null value removes the key.
+//   - Lists of maps identified by a name or rest_name key, like attributes,
+//     relations or parameters, are merged by key. New items are appended,
+//     and an item with "$patch: delete" removes the matching item. Include
+//     items are appended, and the items they include cannot be patched.
+//   - Other values, including the other lists, are replaced.
+func loadOverlaidSpecification(specPath string, overlays []string) (Specification, error) {
+
+	data, err := os.ReadFile(specPath) // #nosec
+	if err != nil {
+		return nil, err
+	}
+
+	var raw yaml.MapSlice
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: unable to decode spec yaml: %s", path.Base(specPath), err)
+	}
+
+	var doc any = raw
+
+	for _, dir := range overlays {
+
+		overlayPath := filepath.Join(dir, path.Base(specPath))
+
+		pdata, err := os.ReadFile(overlayPath) // #nosec
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var patch yaml.MapSlice
+		if err := yaml.Unmarshal(pdata, &patch); err != nil {
+			return nil, fmt.Errorf("overlay %s: unable to decode yaml: %s", overlayPath, err)
+		}
+
+		if doc, err = mergeYAML(doc, patch); err != nil {
+			return nil, fmt.Errorf("overlay %s: %s", overlayPath, err)
+		}
+	}
+
+	merged, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &specification{}
+	spec.path = specPath
+
+	if err = spec.Read(bytes.NewReader(merged), false); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// mergeYAML merges the given patch into the given base.
+func mergeYAML(base any, patch any) (any, error) {
+
+	switch p := patch.(type) {
+
+	case yaml.MapSlice:
+
+		b, ok := base.(yaml.MapSlice)
+		if !ok {
+			return patch, nil
+		}
+
+		out := append(yaml.MapSlice{}, b...)
+
+		for _, item := range p {
+
+			idx := -1
+			for i, bi := range out {
+				if bi.Key == item.Key {
+					idx = i
+					break
+				}
+			}
+
+			switch {
+
+			case item.Value == nil && idx >= 0:
+				out = append(out[:idx], out[idx+1:]...)
+
+			case item.Value == nil:
+
+			case idx >= 0:
+				v, err := mergeYAML(out[idx].Value, item.Value)
+				if err != nil {
+					return nil, fmt.Errorf("%v: %s", item.Key, err)
+				}
+				out[idx].Value = v
+
+			default:
+				out = append(out, item)
+			}
+		}
+
+		return out, nil
+
+	case []any:
+
+		b, ok := base.([]any)
+		if !ok || len(p) == 0 || !isKeyedList(p) || !isKeyedList(b) {
+			return patch, nil
+		}
+
+		out := append([]any{}, b...)
+
+		for _, item := range p {
+
+			// Include items are not keyed and are always added.
+			if mapSliceValue(item.(yaml.MapSlice), includeKey) != nil {
+				out = append(out, item)
+				continue
+			}
+
+			key, id := listItemKey(item.(yaml.MapSlice))
+			del := mapSliceValue(item.(yaml.MapSlice), overlayPatchKey) == overlayPatchDelete
+
+			idx := -1
+			for i, bi := range out {
+				if k, v := listItemKey(bi.(yaml.MapSlice)); k == key && v == id {
+					idx = i
+					break
+				}
+			}
+
+			switch {
+
+			case del && idx < 0:
+				return nil, fmt.Errorf("unable to find item with %s '%v' to delete", key, id)
+
+			case del:
+				out = append(out[:idx], out[idx+1:]...)
+
+			case idx >= 0:
+				v, err := mergeYAML(out[idx], item)
+				if err != nil {
+					return nil, fmt.Errorf("%s '%v': %s", key, id, err)
+				}
+				out[idx] = v
+
+			default:
+				out = append(out, item)
+			}
+		}
+
+		return out, nil
+
+	default:
+		return patch, nil
+	}
+}
+
+// isKeyedList returns true if all the items of the given
+// list are identified by a key or are include items.
+func isKeyedList(list []any) bool {
+
+	for _, item := range list {
+
+		m, ok := item.(yaml.MapSlice)
+		if !ok {
+			return false
+		}
+
+		if key, _ := listItemKey(m); key == "" && mapSliceValue(m, includeKey) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// listItemKey returns the key identifying the given
+// list item and its value.
+func listItemKey(item yaml.MapSlice) (string, any) {
+
+	for _, key := range overlayListKeys {
+		if v := mapSliceValue(item, key); v != nil {
+			return key, v
+		}
+	}
+
+	return "", nil
+}
+
+// mapSliceValue returns the value of the given key.
+func mapSliceValue(m yaml.MapSlice, key string) any {
+
+	for _, item := range m {
+		if item.Key == key {
+			return item.Value
+		}
+	}
+
+	return nil
+}
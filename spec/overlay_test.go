@@ -0,0 +1,192 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const overlayTestTask = `model:
+  private: true
+  aliases: ~
+
+attributes:
+  v1:
+  - name: description
+    $patch: delete
+
+  - name: status
+    default_value: PROGRESS
+    allowed_choices:
+    - PROGRESS
+    - TODO
+
+  - name: owner
+    description: The owner of the task.
+    type: string
+    exposed: true
+    stored: true
+`
+
+func TestOverlay_mergeYAML(t *testing.T) {
+
+	merge := func(base string, patch string) (string, error) {
+
+		var b, p yaml.MapSlice
+		So(yaml.Unmarshal([]byte(base), &b), ShouldBeNil)
+		So(yaml.Unmarshal([]byte(patch), &p), ShouldBeNil)
+
+		out, err := mergeYAML(b, p)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := yaml.Marshal(out)
+		So(err, ShouldBeNil)
+
+		return string(data), nil
+	}
+
+	Convey("Given I merge maps", t, func() {
+
+		out, err := merge("a: 1\nb:\n  c: 2\n  d: 3\ne: 4\n", "b:\n  c: 5\n  f: 6\ne: ~\ng: 7\n")
+
+		Convey("Then the maps should be merged recursively", func() {
+			So(err, ShouldBeNil)
+			So(out, ShouldEqual, "a: 1\nb:\n  c: 5\n  d: 3\n  f: 6\ng: 7\n")
+		})
+	})
+
+	Convey("Given I merge lists", t, func() {
+
+		out, err := merge(
+			"keyed:\n- name: a\n  v: 1\n- name: b\n  v: 2\n- name: c\n  v: 3\nscalars:\n- a\n- b\n",
+			"keyed:\n- name: b\n  v: 4\n- name: a\n  $patch: delete\n- name: d\n  v: 5\nscalars:\n- c\n",
+		)
+
+		Convey("Then keyed lists should be merged and other lists replaced", func() {
+			So(err, ShouldBeNil)
+			So(out, ShouldEqual, "keyed:\n- name: b\n  v: 4\n- name: c\n  v: 3\n- name: d\n  v: 5\nscalars:\n- c\n")
+		})
+	})
+
+	Convey("Given I merge lists identified by rest_name", t, func() {
+
+		out, err := merge(
+			"relations:\n- rest_name: a\n  get:\n    description: a.\n",
+			"relations:\n- rest_name: a\n  create:\n    description: c.\n",
+		)
+
+		Convey("Then the items should be merged", func() {
+			So(err, ShouldBeNil)
+			So(out, ShouldEqual, "relations:\n- rest_name: a\n  get:\n    description: a.\n  create:\n    description: c.\n")
+		})
+	})
+
+	Convey("Given I delete an item that does not exist", t, func() {
+
+		_, err := merge("list:\n- name: a\n", "list:\n- name: b\n  $patch: delete\n")
+
+		Convey("Then err should be correct", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "list: unable to find item with name 'b' to delete")
+		})
+	})
+}
+
+func TestOverlay_LoadSpecificationSet(t *testing.T) {
+
+	Convey("Given I have a spec folder and an overlay", t, func() {
+
+		overlay := t.TempDir()
+		So(os.WriteFile(filepath.Join(overlay, "task.spec"), []byte(overlayTestTask), 0600), ShouldBeNil)
+
+		Convey("When I load it with the overlay", func() {
+
+			set, err := LoadSpecificationSet("./tests", nil, nil, "", OptionOverlays(overlay))
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the model should be patched", func() {
+				model := set.Specification("task").Model()
+				So(model.Private, ShouldBeTrue)
+				So(model.Aliases, ShouldBeEmpty)
+				So(model.Description, ShouldEqual, "Represent a task to do in a listd.")
+			})
+
+			Convey("Then the attributes should be patched", func() {
+				task := set.Specification("task")
+				So(task.Attribute("description", "v1"), ShouldBeNil)
+				So(task.Attribute("status", "v1").DefaultValue, ShouldEqual, "PROGRESS")
+				So(task.Attribute("status", "v1").AllowedChoices, ShouldResemble, []string{"PROGRESS", "TODO"})
+				So(task.Attribute("owner", "v1"), ShouldNotBeNil)
+			})
+
+			Convey("Then the abstracts should be applied after the overlay", func() {
+				So(set.Specification("task").Attribute("ID", "v1"), ShouldNotBeNil)
+			})
+
+			Convey("Then the other specs should not be patched", func() {
+				So(set.Specification("user").Model().Private, ShouldBeFalse)
+			})
+		})
+
+		Convey("When I load it without the overlay", func() {
+
+			set, err := LoadSpecificationSet("./tests", nil, nil, "")
+
+			Convey("Then the spec should not be patched", func() {
+				So(err, ShouldBeNil)
+				So(set.Specification("task").Attribute("description", "v1"), ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given I have an overlay making a spec invalid", t, func() {
+
+		overlay := t.TempDir()
+		So(os.WriteFile(filepath.Join(overlay, "task.spec"), []byte("model:\n  description: no period\n"), 0600), ShouldBeNil)
+
+		Convey("When I load it with the overlay", func() {
+
+			_, err := LoadSpecificationSet("./tests", nil, nil, "", OptionOverlays(overlay))
+
+			Convey("Then the merged spec should be validated", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "task.spec: model description must end with a period")
+			})
+		})
+	})
+
+	Convey("Given I have an overlay patching a spec that does not exist", t, func() {
+
+		overlay := t.TempDir()
+		So(os.WriteFile(filepath.Join(overlay, "nope.spec"), []byte("model:\n  private: true\n"), 0600), ShouldBeNil)
+
+		Convey("When I load it with the overlay", func() {
+
+			_, err := LoadSpecificationSet("./tests", nil, nil, "", OptionOverlays(overlay))
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "overlay "+overlay+": nope.spec: no such file in the specification set")
+			})
+		})
+	})
+}
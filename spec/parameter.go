@@ -40,6 +40,23 @@ func (p *ParameterDefinition) extend(additional *ParameterDefinition, key string
 	return nil
 }
 
+// resolveEnums resolves the enum values of the parameters
+// using the given named enums.
+func (p *ParameterDefinition) resolveEnums(enums EnumMapping) error {
+
+	if p == nil {
+		return nil
+	}
+
+	for _, param := range p.Entries {
+		if err := param.resolveEnum(enums); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Validate validates the parameter definition.
 func (p *ParameterDefinition) Validate(relatedReSTName string) []error {
 
@@ -61,8 +78,65 @@ type Parameter struct {
 	Type           ParameterType `yaml:"type,omitempty"              json:"type,omitempty"`
 	Multiple       bool          `yaml:"multiple,omitempty"          json:"multiple,omitempty"`
 	AllowedChoices []string      `yaml:"allowed_choices,omitempty"   json:"allowed_choices,omitempty"`
+	EnumValues     []*EnumValue  `yaml:"enum_values,omitempty"       json:"enum_values,omitempty"`
+	EnumReference  string        `yaml:"enum_reference,omitempty"    json:"enum_reference,omitempty"`
 	DefaultValue   any           `yaml:"default_value,omitempty"     json:"default_value,omitempty"`
 	ExampleValue   any           `yaml:"example_value,omitempty"     json:"example_value,omitempty"`
+
+	// resolvedEnumValues holds the enum values declared inline
+	// or by the referenced named enum once the set is loaded.
+	// AllowedChoices is then derived from them.
+	resolvedEnumValues []*EnumValue
+}
+
+// MarshalYAML implements yaml.Marshaler. It marshals the
+// parameter as declared in the specification.
+func (p *Parameter) MarshalYAML() (any, error) {
+
+	type parameter Parameter
+
+	return (*parameter)(p.declared()), nil
+}
+
+// declared returns the parameter as declared in the specification,
+// without the allowed choices derived from its enum values.
+func (p *Parameter) declared() *Parameter {
+
+	if p.resolvedEnumValues == nil {
+		return p
+	}
+
+	c := *p
+	c.AllowedChoices = nil
+
+	return &c
+}
+
+// Choices returns the documented values of the enum, declared inline
+// or by the referenced named enum. It returns nil if the values are
+// only declared by allowed_choices or if the set is not loaded yet.
+func (p *Parameter) Choices() []*EnumValue {
+	return p.resolvedEnumValues
+}
+
+// resolveEnum resolves the enum values of the parameter using the
+// given named enums and sets AllowedChoices. Nothing is resolved if
+// allowed_choices is declared too, so the validation reports it.
+func (p *Parameter) resolveEnum(enums EnumMapping) error {
+
+	if p.resolvedEnumValues != nil || len(p.AllowedChoices) > 0 || (p.EnumValues == nil && p.EnumReference == "") {
+		return nil
+	}
+
+	values, err := resolveEnumValues(enums, p.EnumValues, p.EnumReference)
+	if err != nil {
+		return fmt.Errorf("parameter '%s': %w", p.Name, err)
+	}
+
+	p.resolvedEnumValues = values
+	p.AllowedChoices = enumChoices(values)
+
+	return nil
 }
 
 // Validate validates the parameter definition.
@@ -71,7 +145,7 @@ func (p *Parameter) Validate(relatedReSTName string) []error {
 	var errs []error
 
 	if p.Description == "" || p.Description[len(p.Description)-1] != '.' {
-		errs = append(errs, fmt.Errorf("%s.spec: description of parameter '%s' must end with a period", relatedReSTName, p.Name))
+		errs = append(errs, NewLintError(LintRuleParameterDescriptionPeriod, nil, nil, fmt.Errorf("%s.spec: description of parameter '%s' must end with a period", relatedReSTName, p.Name)))
 	}
 
 	if p.Type == "" {
@@ -89,16 +163,14 @@ func (p *Parameter) Validate(relatedReSTName string) []error {
 		}
 	}
 
-	if p.Type == ParameterTypeEnum && len(p.AllowedChoices) == 0 {
-		errs = append(errs, fmt.Errorf("%s.spec: enum parameter '%s' must define allowed_choices", relatedReSTName, p.Name))
-	}
+	errs = append(errs, validateEnumDeclaration(relatedReSTName+".spec", "parameter", p.Name, p.Type == ParameterTypeEnum, p.declared().AllowedChoices, p.EnumValues, p.EnumReference)...)
 
 	if p.Type != ParameterTypeEnum && len(p.AllowedChoices) > 0 {
 		errs = append(errs, fmt.Errorf("%s.spec: parameter '%s' is not an enum but defines allowed_choices", relatedReSTName, p.Name))
 	}
 
 	if p.DefaultValue == nil && p.ExampleValue == nil && p.Type == ParameterTypeString {
-		errs = append(errs, fmt.Errorf("%s.spec: parameter '%s' must provide an example value as it doesn't have a default", relatedReSTName, p.Name))
+		errs = append(errs, NewLintError(LintRuleParameterExampleValue, nil, nil, fmt.Errorf("%s.spec: parameter '%s' must provide an example value as it doesn't have a default", relatedReSTName, p.Name)))
 	}
 
 	if p.DefaultValue != nil {
@@ -101,7 +101,7 @@ func TestParameter_Validate(t *testing.T) {
 				"spec",
 			},
 			[]error{
-				fmt.Errorf("spec.spec: enum parameter 'p' must define allowed_choices"),
+				fmt.Errorf("spec.spec: enum parameter 'p' must define allowed_choices, enum_values or enum_reference"),
 			},
 		},
 		{
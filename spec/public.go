@@ -0,0 +1,163 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IsPublic returns true if the given model is part of the public
+// output, that is, if it is not private or if it is private but
+// has the forceDocumentation extension set to anything but false.
+func IsPublic(model *Model) bool {
+
+	if !model.Private {
+		return true
+	}
+
+	force, ok := model.Extensions["forceDocumentation"]
+
+	return ok && force != false
+}
+
+// A publicOutput is an output containing only the public
+// part of a SpecificationSet.
+type publicOutput struct {
+	name      string
+	published func(*Model) bool
+}
+
+// publicOutputs are the public outputs of the rego commands. The
+// documentation honours the forceDocumentation extension while the
+// public JSON schema, that uses SelectorPublic, does not.
+var publicOutputs = []publicOutput{
+	{
+		name:      "the documentation",
+		published: IsPublic,
+	},
+	{
+		name:      "the public JSON schema",
+		published: func(m *Model) bool { return !m.Private },
+	},
+}
+
+// CheckPublicConsistency returns an error for every reference from the
+// public part of the given SpecificationSet to content that is not part
+// of it. These are the exposed ref, refList and refMap attributes and the
+// relations of public specifications pointing to a private specification
+// or to a specification without exposed attributes. As the documentation
+// and the public JSON schema do not contain the same specifications, the
+// errors mention the outputs they apply to. References to specifications
+// that are not in the set are ignored.
+func CheckPublicConsistency(set SpecificationSet) []error {
+
+	var errs []error
+
+	for _, s := range set.Specifications() {
+
+		model := s.Model()
+
+		for _, attr := range withProperties(s.ExposedAttributes(s.LatestAttributesVersion())) {
+
+			switch attr.Type {
+			case AttributeTypeRef, AttributeTypeRefList, AttributeTypeRefMap:
+			default:
+				continue
+			}
+
+			for _, leak := range publicLeaks(model, set.Specification(attr.SubType)) {
+				errs = append(errs, NewLintError(
+					LintRulePublicConsistency,
+					nil,
+					attr,
+					fmt.Errorf("%s.spec: exposed attribute '%s' references %s '%s' in %s", model.RestName, attr.qualifiedName(), leak.reason, attr.SubType, leak.outputs),
+				))
+			}
+		}
+
+		for _, rel := range s.Relations() {
+
+			for _, leak := range publicLeaks(model, set.Specification(rel.RestName)) {
+				errs = append(errs, NewLintError(
+					LintRulePublicConsistency,
+					model,
+					nil,
+					fmt.Errorf("%s.spec: relation to %s '%s' in %s", model.RestName, leak.reason, rel.RestName, leak.outputs),
+				))
+			}
+		}
+	}
+
+	return errs
+}
+
+// A publicLeak describes why a reference leaks
+// content that is not part of some public outputs.
+type publicLeak struct {
+	reason  string
+	outputs string
+}
+
+// publicLeaks returns the leaks of a reference from the given model to
+// the given specification, grouped by reason, in the public outputs
+// containing the model.
+func publicLeaks(from *Model, to Specification) []publicLeak {
+
+	var (
+		reasons []string
+		outputs = map[string][]string{}
+	)
+
+	for _, o := range publicOutputs {
+
+		if !o.published(from) {
+			continue
+		}
+
+		reason := unpublishedReason(to, o)
+		if reason == "" {
+			continue
+		}
+
+		if _, ok := outputs[reason]; !ok {
+			reasons = append(reasons, reason)
+		}
+		outputs[reason] = append(outputs[reason], o.name)
+	}
+
+	leaks := make([]publicLeak, len(reasons))
+	for i, reason := range reasons {
+		leaks[i] = publicLeak{
+			reason:  reason,
+			outputs: strings.Join(outputs[reason], " and "),
+		}
+	}
+
+	return leaks
+}
+
+// unpublishedReason returns why the given specification is not part
+// of the given public output, or an empty string if it is.
+func unpublishedReason(s Specification, output publicOutput) string {
+
+	switch {
+	case s == nil:
+		return ""
+	case !output.published(s.Model()):
+		return "private specification"
+	case !s.Model().IsRoot && len(s.ExposedAttributes(s.LatestAttributesVersion())) == 0:
+		return "specification without exposed attributes"
+	default:
+		return ""
+	}
+}
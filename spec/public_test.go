@@ -0,0 +1,178 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const publicTestAssignee = `  - name: assignee
+    description: The user assigned to the task.
+    type: ref
+    subtype: user
+    exposed: true
+
+`
+
+func TestPublic_CheckPublicConsistency(t *testing.T) {
+
+	task, _ := os.ReadFile("./tests/task.spec")
+	user, _ := os.ReadFile("./tests/user.spec")
+
+	taskWithRef := strings.Replace(string(task), "  - name: description\n", publicTestAssignee+"  - name: description\n", 1)
+
+	Convey("Given I have a spec folder without private specifications", t, func() {
+
+		set, err := LoadSpecificationSet(copyTestFolder(t, map[string]string{"task.spec": taskWithRef}), nil, nil, "")
+		So(err, ShouldBeNil)
+
+		Convey("When I check its consistency", func() {
+
+			errs := CheckPublicConsistency(set)
+
+			Convey("Then there should be no error", func() {
+				So(errs, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with a private specification referenced by public ones", t, func() {
+
+		set, err := LoadSpecificationSet(
+			copyTestFolder(t, map[string]string{
+				"task.spec": taskWithRef,
+				"user.spec": strings.Replace(string(user), "  group: core\n", "  group: core\n  private: true\n", 1),
+			}),
+			nil,
+			nil,
+			"",
+		)
+		So(err, ShouldBeNil)
+
+		Convey("When I check its consistency", func() {
+
+			errs := CheckPublicConsistency(set)
+
+			Convey("Then every reference should be reported", func() {
+				So(errorStrings(errs), ShouldResemble, []string{
+					"list.spec: relation to private specification 'user' in the documentation and the public JSON schema",
+					"root.spec: relation to private specification 'user' in the documentation and the public JSON schema",
+					"task.spec: exposed attribute 'assignee' references private specification 'user' in the documentation and the public JSON schema",
+				})
+			})
+
+			Convey("Then the errors should be reported by the public-consistency rule", func() {
+				for _, err := range errs {
+					So(err.(*LintError).RuleID, ShouldEqual, LintRulePublicConsistency)
+					So(err.(*LintError).Severity, ShouldEqual, LintSeverityWarning)
+				}
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with a private specification forced in the documentation", t, func() {
+
+		set, err := LoadSpecificationSet(
+			copyTestFolder(t, map[string]string{
+				"task.spec": taskWithRef,
+				"user.spec": strings.Replace(string(user), "  group: core\n", "  group: core\n  private: true\n  extensions:\n    forceDocumentation: true\n", 1),
+			}),
+			nil,
+			nil,
+			"",
+		)
+		So(err, ShouldBeNil)
+
+		Convey("When I check its consistency", func() {
+
+			errs := CheckPublicConsistency(set)
+
+			Convey("Then only the references in the public JSON schema should be reported", func() {
+				So(errorStrings(errs), ShouldResemble, []string{
+					"list.spec: relation to private specification 'user' in the public JSON schema",
+					"root.spec: relation to private specification 'user' in the public JSON schema",
+					"task.spec: exposed attribute 'assignee' references private specification 'user' in the public JSON schema",
+				})
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with a private specification forced in the documentation with a legacy value", t, func() {
+
+		set, err := LoadSpecificationSet(
+			copyTestFolder(t, map[string]string{
+				"task.spec": taskWithRef,
+				"user.spec": strings.Replace(string(user), "  group: core\n", "  group: core\n  private: true\n  extensions:\n    forceDocumentation: enabled\n", 1),
+			}),
+			nil,
+			nil,
+			"",
+		)
+		So(err, ShouldBeNil)
+
+		Convey("Then the specification should be public", func() {
+			So(IsPublic(set.Specification("user").Model()), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given I have a spec folder with a private specification referenced in a later version", t, func() {
+
+		set, err := LoadSpecificationSet(
+			copyTestFolder(t, map[string]string{
+				"task.spec": string(task) + "\n  v2:\n" + publicTestAssignee,
+				"user.spec": strings.Replace(string(user), "  group: core\n", "  group: core\n  private: true\n", 1),
+			}),
+			nil,
+			nil,
+			"",
+		)
+		So(err, ShouldBeNil)
+
+		Convey("When I check its consistency", func() {
+
+			errs := CheckPublicConsistency(set)
+
+			Convey("Then the reference should be reported", func() {
+				So(errorStrings(errs), ShouldContain, "task.spec: exposed attribute 'assignee' references private specification 'user'"+
+					" in the documentation and the public JSON schema")
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder with a public specification without exposed attributes", t, func() {
+
+		set, err := LoadSpecificationSet(
+			copyTestFolder(t, map[string]string{
+				"task.spec": strings.NewReplacer("    exposed: true\n", "", "  extends:\n  - '@base'\n", "").Replace(taskWithRef),
+			}),
+			nil,
+			nil,
+			"",
+		)
+		So(err, ShouldBeNil)
+
+		Convey("When I check its consistency", func() {
+
+			errs := CheckPublicConsistency(set)
+
+			Convey("Then the relation to it should be reported", func() {
+				So(errorStrings(errs), ShouldResemble, []string{
+					"list.spec: relation to specification without exposed attributes 'task' in the documentation and the public JSON schema",
+				})
+			})
+		})
+	})
+}
@@ -27,11 +27,11 @@ func (ra *RelationAction) Validate(currentRestName string, remoteRestName string
 	var errs []error
 
 	if ra.Description == "" {
-		errs = append(errs, fmt.Errorf("%s.spec: relation '%s' to '%s' must have a description", currentRestName, k, remoteRestName))
+		errs = append(errs, NewLintError(LintRuleRelationDescription, nil, nil, fmt.Errorf("%s.spec: relation '%s' to '%s' must have a description", currentRestName, k, remoteRestName)))
 	}
 
 	if ra.Description != "" && ra.Description[len(ra.Description)-1] != '.' {
-		errs = append(errs, fmt.Errorf("%s.spec: relation '%s' to '%s' description must end with a period", currentRestName, k, remoteRestName))
+		errs = append(errs, NewLintError(LintRuleRelationDescriptionPeriod, nil, nil, fmt.Errorf("%s.spec: relation '%s' to '%s' description must end with a period", currentRestName, k, remoteRestName)))
 	}
 
 	if ra.ParameterDefinition != nil {
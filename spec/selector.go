@@ -0,0 +1,195 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Selector returns true if the given Specification is selected.
+type Selector func(s Specification) bool
+
+// SelectorPublic selects the specifications that are not private.
+const SelectorPublic = "!private"
+
+// ParseSelector parses the given selector expression. An expression is
+// a comma separated list of terms, and a specification is selected when
+// it matches all of them. A term can be:
+//
+//   - private: the model is private.
+//   - tag:<tag>: the model has the given tag.
+//   - <field>=<value>: the given field of the model has the given value.
+//     Supported fields are group, package and rest_name.
+//
+// A term can be negated with a leading '!', and "<field>!=<value>"
+// is a shortcut for "!<field>=<value>". An empty expression selects
+// all the specifications.
+func ParseSelector(expr string) (Selector, error) {
+
+	var terms []Selector
+
+	for _, raw := range strings.Split(expr, ",") {
+
+		term := strings.TrimSpace(raw)
+		if term == "" {
+			continue
+		}
+
+		sel, err := parseSelectorTerm(term)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector '%s': %w", expr, err)
+		}
+
+		terms = append(terms, sel)
+	}
+
+	return func(s Specification) bool {
+		for _, t := range terms {
+			if !t(s) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func parseSelectorTerm(term string) (Selector, error) {
+
+	negate := strings.HasPrefix(term, "!")
+	term = strings.TrimPrefix(term, "!")
+
+	if k, v, ok := strings.Cut(term, "!="); ok {
+		if negate {
+			return nil, fmt.Errorf("term '!%s' cannot be negated twice", term)
+		}
+		negate = true
+		term = k + "=" + v
+	}
+
+	var sel Selector
+
+	switch {
+
+	case term == "private":
+		sel = func(s Specification) bool { return s.Model().Private }
+
+	case strings.HasPrefix(term, "tag:"):
+		tag := strings.TrimPrefix(term, "tag:")
+		if tag == "" {
+			return nil, fmt.Errorf("term 'tag:' must have a value")
+		}
+		sel = func(s Specification) bool { return containsString(s.Model().Tags, tag) }
+
+	case strings.Contains(term, "="):
+		field, value, _ := strings.Cut(term, "=")
+		get, err := selectorField(field)
+		if err != nil {
+			return nil, err
+		}
+		sel = func(s Specification) bool { return get(s.Model()) == value }
+
+	default:
+		return nil, fmt.Errorf("unknown term '%s'", term)
+	}
+
+	if negate {
+		return func(s Specification) bool { return !sel(s) }, nil
+	}
+
+	return sel, nil
+}
+
+func selectorField(field string) (func(*Model) string, error) {
+
+	switch field {
+	case "group":
+		return func(m *Model) string { return m.Group }, nil
+	case "package":
+		return func(m *Model) string { return m.Package }, nil
+	case "rest_name":
+		return func(m *Model) string { return m.RestName }, nil
+	default:
+		return nil, fmt.Errorf("unknown field '%s': must be group, package or rest_name", field)
+	}
+}
+
+// Select returns a new SpecificationSet containing the specifications of
+// the given set that are selected by the given Selector. The root
+// specification is always kept. The relations pointing to specifications
+// that are not selected are removed. The ref, refList and refMap attributes
+// are kept as they are part of the models: the public-consistency lint rule
+// reports the ones of the public outputs pointing to private specifications.
+// The specifications of the given set are not modified. It returns an error if the specifications have not
+// been created by this package.
+func Select(set SpecificationSet, selector Selector) (SpecificationSet, error) {
+
+	selected := map[string]*specification{}
+
+	for _, s := range set.Specifications() {
+
+		if !s.Model().IsRoot && !selector(s) {
+			continue
+		}
+
+		orig, ok := s.(*specification)
+		if !ok {
+			return nil, fmt.Errorf("unable to select specification '%s': unsupported implementation %T", s.Model().RestName, s)
+		}
+
+		c := *orig
+		selected[s.Model().RestName] = &c
+	}
+
+	out := &specificationSet{
+		configuration:  set.Configuration(),
+		typeMap:        set.TypeMapping(),
+		validationsMap: set.ValidationMapping(),
+		apiInfo:        set.APIInfo(),
+		specs:          make(map[string]Specification, len(selected)),
+	}
+
+	if p, ok := set.(DefinitionProvider); ok {
+		out.parametersMap = p.ParameterMapping()
+		out.extensionsMap = p.ExtensionMapping()
+		out.enumsMap = p.EnumMapping()
+		out.abstracts = p.Abstracts()
+	}
+
+	if p, ok := set.(CommitProvider); ok {
+		out.commit = p.Commit()
+	}
+
+	if s, ok := set.(*specificationSet); ok {
+		out.strictExtensions = s.strictExtensions
+	}
+
+	for name, s := range selected {
+
+		var relations []*Relation
+		for _, rel := range s.RawRelations {
+			if _, ok := selected[rel.RestName]; ok {
+				relations = append(relations, rel)
+			}
+		}
+
+		s.RawRelations = relations
+		s.relationsMap = relationMapping{}
+		for _, rel := range relations {
+			s.relationsMap[rel.RestName] = rel
+		}
+
+		out.specs[name] = s
+	}
+
+	return out, nil
+}
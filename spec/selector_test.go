@@ -0,0 +1,253 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSelector_ParseSelector(t *testing.T) {
+
+	model := func(m *Model) Specification {
+		return &specification{RawModel: m}
+	}
+
+	task := model(&Model{RestName: "task", Group: "core", Package: "todo-list", Tags: []string{"beta"}})
+	secret := model(&Model{RestName: "secret", Group: "security", Package: "policy", Private: true})
+
+	Convey("Given I parse valid selectors", t, func() {
+
+		tests := []struct {
+			expr   string
+			task   bool
+			secret bool
+		}{
+			{"", true, true},
+			{"private", false, true},
+			{"!private", true, false},
+			{"tag:beta", true, false},
+			{"!tag:beta", false, true},
+			{"group=core", true, false},
+			{"group!=core", false, true},
+			{"package=policy", false, true},
+			{"rest_name=task", true, false},
+			{"group=core, !private", true, false},
+			{"group=core,private", false, false},
+		}
+
+		for _, tt := range tests {
+
+			sel, err := ParseSelector(tt.expr)
+
+			Convey("Then '"+tt.expr+"' should select the correct specifications", func() {
+				So(err, ShouldBeNil)
+				So(sel(task), ShouldEqual, tt.task)
+				So(sel(secret), ShouldEqual, tt.secret)
+			})
+		}
+	})
+
+	Convey("Given I parse invalid selectors", t, func() {
+
+		tests := map[string]string{
+			"public":        "invalid selector 'public': unknown term 'public'",
+			"tag:":          "invalid selector 'tag:': term 'tag:' must have a value",
+			"owner=bob":     "invalid selector 'owner=bob': unknown field 'owner': must be group, package or rest_name",
+			"!group!=core":  "invalid selector '!group!=core': term '!group!=core' cannot be negated twice",
+			"group=core,oo": "invalid selector 'group=core,oo': unknown term 'oo'",
+		}
+
+		for expr, expected := range tests {
+
+			_, err := ParseSelector(expr)
+
+			Convey("Then parsing '"+expr+"' should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, expected)
+			})
+		}
+	})
+}
+
+func TestSelector_Select(t *testing.T) {
+
+	Convey("Given I have a specification set", t, func() {
+
+		set, err := LoadSpecificationSet("./tests", nil, nil, "")
+		So(err, ShouldBeNil)
+
+		Convey("When I select the specifications that are not the list", func() {
+
+			sel, err := ParseSelector("rest_name!=list")
+			So(err, ShouldBeNil)
+
+			out, err := Select(set, sel)
+			So(err, ShouldBeNil)
+
+			Convey("Then the list should not be selected", func() {
+				So(out.Len(), ShouldEqual, 3)
+				So(out.Specification("list"), ShouldBeNil)
+				So(out.Specification("task"), ShouldNotBeNil)
+				So(out.Specification("user"), ShouldNotBeNil)
+			})
+
+			Convey("Then the root should be kept and its relation to the list pruned", func() {
+				root := out.Specification("root")
+				So(root, ShouldNotBeNil)
+				So(len(root.Relations()), ShouldEqual, 1)
+				So(root.Relations()[0].RestName, ShouldEqual, "user")
+				So(root.Relation("list"), ShouldBeNil)
+				So(root.Relation("user"), ShouldNotBeNil)
+			})
+
+			Convey("Then the rest of the set should be kept", func() {
+				So(out.Configuration(), ShouldEqual, set.Configuration())
+				So(out.TypeMapping(), ShouldResemble, set.TypeMapping())
+				So(out.(DefinitionProvider).Abstracts(), ShouldResemble, set.(DefinitionProvider).Abstracts())
+			})
+
+			Convey("Then the original set should not be modified", func() {
+				So(set.Len(), ShouldEqual, 4)
+				So(len(set.Specification("root").Relations()), ShouldEqual, 2)
+				So(set.Specification("root").Relation("list"), ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+const selectorTestReferences = `  - name: assignee
+    description: The user assigned to the task.
+    type: ref
+    subtype: user
+    exposed: true
+
+  - name: owner
+    description: The owner of the task.
+    type: object
+    exposed: true
+    properties:
+    - name: user
+      description: The user owning the task.
+      type: ref
+      subtype: user
+    - name: name
+      description: The name of the owner.
+      type: string
+
+`
+
+// selectorTestSpecification is a Specification
+// that has not been created by the spec package.
+type selectorTestSpecification struct {
+	Specification
+}
+
+func TestSelector_SelectReferences(t *testing.T) {
+
+	task, _ := os.ReadFile("./tests/task.spec")
+
+	Convey("Given I have a specification set with references", t, func() {
+
+		set, err := LoadSpecificationSet(
+			copyTestFolder(t, map[string]string{
+				"task.spec": strings.Replace(string(task), "  - name: description\n", selectorTestReferences+"  - name: description\n", 1),
+			}),
+			nil,
+			nil,
+			"",
+		)
+		So(err, ShouldBeNil)
+
+		Convey("When I select the specifications that are not the user", func() {
+
+			sel, err := ParseSelector("rest_name!=user")
+			So(err, ShouldBeNil)
+
+			out, err := Select(set, sel)
+			So(err, ShouldBeNil)
+
+			Convey("Then the references to the user should be kept", func() {
+				s := out.Specification("task")
+				So(s.Attribute("assignee", "v1"), ShouldNotBeNil)
+				So(len(s.Attribute("owner", "v1").Properties), ShouldEqual, 2)
+			})
+
+			Convey("Then the relations to the user should be removed", func() {
+				So(out.Specification("root").Relation("user"), ShouldBeNil)
+				So(set.Specification("root").Relation("user"), ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given I have a specification set containing a foreign Specification", t, func() {
+
+		set, err := LoadSpecificationSet("./tests", nil, nil, "")
+		So(err, ShouldBeNil)
+
+		set.(*specificationSet).specs["task"] = selectorTestSpecification{set.Specification("task")}
+
+		Convey("When I select it", func() {
+
+			sel, err := ParseSelector("")
+			So(err, ShouldBeNil)
+
+			_, err = Select(set, sel)
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "unable to select specification 'task': unsupported implementation spec.selectorTestSpecification")
+			})
+		})
+	})
+}
+
+func TestSelector_Load(t *testing.T) {
+
+	Convey("Given I have a spec folder with tagged specifications", t, func() {
+
+		task, _ := os.ReadFile("./tests/task.spec")
+
+		dir := copyTestFolder(t, map[string]string{
+			"task.spec": strings.Replace(string(task), "  group: core\n", "  group: core\n  tags:\n  - beta\n", 1),
+		})
+
+		Convey("When I load it with a selector", func() {
+
+			set, err := Load(context.Background(), NewDirectorySource(dir), OptionSelect("tag:beta"))
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then only the root and the tagged specification should be loaded", func() {
+				So(set.Len(), ShouldEqual, 2)
+				So(set.Specification("task").Model().Tags, ShouldResemble, []string{"beta"})
+				So(set.Specification("root").Relations(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When I load it with an invalid selector", func() {
+
+			_, err := Load(context.Background(), NewDirectorySource(dir), OptionSelect("nope"))
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "invalid selector 'nope': unknown term 'nope'")
+			})
+		})
+	})
+}
@@ -12,17 +12,12 @@
 package spec
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
 	"path"
 	"sort"
 	"strings"
-
-	git "gopkg.in/src-d/go-git.v4"
-	"gopkg.in/src-d/go-git.v4/plumbing"
-	"gopkg.in/src-d/go-git.v4/plumbing/transport"
-	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
 )
 
 // AttributeNameConverterFunc is the type of a attribute name conveter.
@@ -38,123 +33,142 @@ type specificationSet struct {
 	validationsMap ValidationMapping
 	apiInfo        *APIInfo
 	parametersMap  ParameterMapping
+	extensionsMap  ExtensionMapping
+	enumsMap       EnumMapping
+
+	// strictExtensions is true when the extensions are declared
+	// in an _extensions.mapping file. In that case, undeclared
+	// extensions are reported.
+	strictExtensions bool
+
+	specs     map[string]Specification
+	abstracts map[string]Specification
 
-	specs map[string]Specification
+	// commit is the hash of the commit the
+	// specifications were loaded from, if any.
+	commit string
 }
 
-// LoadSpecificationSetFromGithub loads a set of specs from github.
-func LoadSpecificationSetFromGithub(
-	token string,
-	repoURL string,
-	refName string,
-	internalPath string,
-	nameConvertFunc AttributeNameConverterFunc,
-	typeConvertFunc AttributeTypeConverterFunc,
-	typeMappingName string,
-) (SpecificationSet, error) {
+// Load loads and parses the specification set provided by the given Source.
+// The lint warnings do not prevent the set from loading. They are logged
+// using the logger set by OptionLogger, which discards them by default, or
+// made fatal by OptionStrict. LintSpecificationSet returns all of them.
+func Load(ctx context.Context, source Source, opts ...LoadOption) (SpecificationSet, error) {
 
-	var auth transport.AuthMethod
-	if token != "" {
-		auth = &http.BasicAuth{
-			Username: "Bearer",
-			Password: token,
-		}
-	}
+	cfg := newLoadConfig(opts...)
 
-	tmpFolder, err := os.MkdirTemp("", "regolithe-refs-head")
+	selector, err := ParseSelector(cfg.selector)
 	if err != nil {
 		return nil, err
 	}
-	defer func(f string) { _ = os.RemoveAll(f) }(tmpFolder) // nolint: errcheck
 
-	var (
-		ref           plumbing.ReferenceName
-		needsCheckout bool
-	)
-
-	givenHash := plumbing.NewHash(refName)
-	if !givenHash.IsZero() {
-		ref = plumbing.NewReferenceFromStrings("refs/heads/master", "").Name()
-		needsCheckout = true
-	} else {
-		ref = plumbing.NewReferenceFromStrings("refs/heads/"+refName, "").Name()
+	fetched, err := source.Fetch(ctx, FetchOptions{
+		Logger:   cfg.logger,
+		Progress: cfg.progress,
+	})
+	if err != nil {
+		return nil, err
 	}
+	defer fetched.Release()
 
-	log.Printf("Retrieving repository: ref=%s repo=%s path=%s", refName, repoURL, internalPath)
-
-	cloneFunc := func(folder string, ref plumbing.ReferenceName) (*git.Repository, error) {
-		return git.PlainClone(
-			folder,
-			false,
-			&git.CloneOptions{
-				URL:           repoURL,
-				Progress:      nil,
-				ReferenceName: ref,
-				Auth:          auth,
-			})
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	repo, err := cloneFunc(tmpFolder, ref)
-
+	set, errs, err := loadSpecificationSet(fetched.Path, cfg.nameConvertFunc, cfg.typeConvertFunc, cfg.mappingMode, cfg.overlays)
 	if err != nil {
-		if err == plumbing.ErrReferenceNotFound {
+		return nil, err
+	}
 
-			log.Printf("failed to clone with refs/heads: ref=%s repo=%s path=%s err=%s", refName, repoURL, internalPath, err)
+	set.commit = fetched.Commit
 
-			// Need to recreate a folder, get error repository already created otherwise
-			// Happened even if old tmp folder is deleted...
-			tmpFolder, err = os.MkdirTemp("", "regolithe-refs-tags")
-			if err != nil {
-				return nil, err
-			}
-			defer func(f string) { _ = os.RemoveAll(f) }(tmpFolder) // nolint: errcheck
+	errs = append(errs, checkLintRules(set)...)
+	errs = append(errs, checkValidationRules(set, cfg.rules)...)
 
-			ref = plumbing.NewReferenceFromStrings("refs/tags/"+refName, "").Name()
-			repo, err = cloneFunc(tmpFolder, ref)
+	fatal, warnings := lint(set.configuration, errs)
 
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			return nil, err
+	for _, w := range warnings {
+		if cfg.strict {
+			fatal = append(fatal, w)
+			continue
 		}
+		cfg.logger.Warn(w.Error(), "rule", w.RuleID)
 	}
 
-	if needsCheckout {
-		wt, e := repo.Worktree()
-		if e != nil {
-			return nil, e
-		}
-
-		if err = wt.Checkout(
-			&git.CheckoutOptions{
-				Hash: givenHash,
-			}); err != nil {
-			return nil, err
-		}
+	if len(fatal) > 0 {
+		return nil, formatValidationErrors(fatal)
 	}
 
-	set, err := LoadSpecificationSet(
-		path.Join(tmpFolder, internalPath),
-		nameConvertFunc,
-		typeConvertFunc,
-		typeMappingName,
-	)
-	if err != nil {
-		return nil, err
+	if cfg.selector != "" {
+		return Select(set, selector)
 	}
 
 	return set, nil
 }
 
+// LoadSpecificationSetFromGithub loads a set of specs from github.
+// It is a shortcut for Load with a Source returned by NewGithubSource.
+func LoadSpecificationSetFromGithub(
+	token string,
+	repoURL string,
+	refName string,
+	internalPath string,
+	nameConvertFunc AttributeNameConverterFunc,
+	typeConvertFunc AttributeTypeConverterFunc,
+	typeMappingName string,
+	opts ...LoadOption,
+) (SpecificationSet, error) {
+
+	return Load(
+		context.Background(),
+		NewGithubSource(token, repoURL, refName, internalPath),
+		append(
+			[]LoadOption{
+				OptionNameConverter(nameConvertFunc),
+				OptionTypeConverter(typeConvertFunc),
+				OptionMappingMode(typeMappingName),
+			},
+			opts...,
+		)...,
+	)
+}
+
 // LoadSpecificationSet loads and parses all specification in a folder.
+// It is a shortcut for Load with a Source returned by NewDirectorySource.
+// As with Load, the lint warnings are only reported through OptionLogger.
 func LoadSpecificationSet(
 	dirname string,
 	nameConvertFunc AttributeNameConverterFunc,
 	typeConvertFunc AttributeTypeConverterFunc,
 	typeMappingName string,
+	opts ...LoadOption,
 ) (SpecificationSet, error) {
 
+	return Load(
+		context.Background(),
+		NewDirectorySource(dirname),
+		append(
+			[]LoadOption{
+				OptionNameConverter(nameConvertFunc),
+				OptionTypeConverter(typeConvertFunc),
+				OptionMappingMode(typeMappingName),
+			},
+			opts...,
+		)...,
+	)
+}
+
+// loadSpecificationSet loads and resolves all specifications in a folder.
+// It returns the validation errors separately so the caller can decide
+// how to deal with them.
+func loadSpecificationSet(
+	dirname string,
+	nameConvertFunc AttributeNameConverterFunc,
+	typeConvertFunc AttributeTypeConverterFunc,
+	typeMappingName string,
+	overlays []string,
+) (*specificationSet, []error, error) {
+
 	var loadedRegolitheINI bool
 
 	set := &specificationSet{
@@ -163,10 +177,15 @@ func LoadSpecificationSet(
 
 	filesInfo, err := os.ReadDir(dirname)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if err = checkOverlays(dirname, overlays); err != nil {
+		return nil, nil, err
 	}
 
 	baseSpecs := map[string]Specification{}
+	set.abstracts = baseSpecs
 
 	for _, info := range filesInfo {
 
@@ -176,7 +195,7 @@ func LoadSpecificationSet(
 
 			set.configuration, err = LoadConfig(path.Join(dirname, info.Name()))
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			loadedRegolitheINI = true
@@ -185,27 +204,43 @@ func LoadSpecificationSet(
 
 			set.typeMap, err = LoadTypeMapping(path.Join(dirname, info.Name()))
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 		case "_validation.mapping":
 
 			set.validationsMap, err = LoadValidationMapping(path.Join(dirname, info.Name()))
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 		case "_parameter.mapping":
 
 			set.parametersMap, err = LoadGlobalParameters(path.Join(dirname, info.Name()))
 			if err != nil {
-				return nil, err
+				return nil, nil, err
+			}
+
+		case "_extensions.mapping":
+
+			set.extensionsMap, err = LoadExtensionMapping(path.Join(dirname, info.Name()))
+			if err != nil {
+				return nil, nil, err
+			}
+
+			set.strictExtensions = true
+
+		case "_enums.mapping":
+
+			set.enumsMap, err = LoadEnumMapping(path.Join(dirname, info.Name()))
+			if err != nil {
+				return nil, nil, err
 			}
 
 		case "_api.info":
 			set.apiInfo, err = LoadAPIInfo(path.Join(dirname, info.Name()))
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 		default:
@@ -220,23 +255,39 @@ func LoadSpecificationSet(
 				targetMap = baseSpecs
 			}
 
-			baseName := strings.Replace(strings.Replace(info.Name(), ".spec", "", 1), ".abs", "", 1)
-			baseName = strings.TrimPrefix(baseName, "+")
-
-			targetMap[baseName], err = LoadSpecification(path.Join(dirname, info.Name()), false)
+			baseName, spec, err := loadSpecificationFile(dirname, info.Name(), overlays)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
-			if targetMap[baseName].Model() != nil &&
-				targetMap[baseName].Model().RestName != baseName {
-				return nil, fmt.Errorf("%s: declared rest_name '%s' must be identical to filename without extension", info.Name(), targetMap[baseName].Model().RestName)
-			}
+			targetMap[baseName] = spec
 		}
 	}
 
 	if !loadedRegolitheINI {
-		return nil, fmt.Errorf("unable to find regolithe.ini in folder '%s'", dirname)
+		return nil, nil, fmt.Errorf("unable to find regolithe.ini in folder '%s'", dirname)
+	}
+
+	if len(set.configuration.Extensions) > 0 {
+
+		if set.extensionsMap == nil {
+			set.extensionsMap = NewExtensionMapping()
+		}
+
+		for _, k := range sortedKeys(set.configuration.Extensions) {
+
+			if _, ok := set.extensionsMap[k]; ok {
+				return nil, nil, fmt.Errorf("regolithe.ini: extension '%s' is already declared in _extensions.mapping", k)
+			}
+
+			set.extensionsMap[k] = set.configuration.Extensions[k]
+		}
+
+		set.strictExtensions = true
+	}
+
+	if err = set.loadDependencies(dirname); err != nil {
+		return nil, nil, err
 	}
 
 	// Massage the specs
@@ -256,7 +307,7 @@ func LoadSpecificationSet(
 
 			base, ok := baseSpecs[ext]
 			if !ok {
-				return nil, fmt.Errorf("unable to find base spec '%s' for spec '%s'", ext, spec.Model().RestName)
+				return nil, nil, fmt.Errorf("unable to find base spec '%s' for spec '%s'", ext, spec.Model().RestName)
 			}
 
 			if !skipInheritOrdering {
@@ -264,7 +315,7 @@ func LoadSpecificationSet(
 			}
 
 			if err = spec.ApplyBaseSpecifications(base); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
 
@@ -275,65 +326,110 @@ func LoadSpecificationSet(
 
 			linked, ok := set.specs[rel.RestName]
 			if !ok {
-				return nil, fmt.Errorf("unable to find related spec '%s' for spec '%s'", rel.RestName, spec.Model().RestName)
+				return nil, nil, fmt.Errorf("unable to find related spec '%s' for spec '%s'", rel.RestName, spec.Model().RestName)
 			}
 
 			rel.remoteSpecification = linked
 		}
 
-		for _, version := range spec.AttributeVersions() {
+		if model := spec.Model(); model != nil {
 
-			for _, attr := range spec.Attributes(version) {
+			if model.ValidationProviders == nil {
+				model.ValidationProviders = map[string]*ValidationMap{}
+			}
 
-				if attr.ValidationProviders == nil {
-					attr.ValidationProviders = map[string]*ValidationMap{}
-				}
+			// Unknown validations are reported whatever the mode,
+			// but they are only resolved with one.
+			if set.validationsMap != nil {
 
-				if nameConvertFunc != nil {
-					attr.ConvertedName = nameConvertFunc(attr.Name)
-				} else {
-					attr.ConvertedName = attr.Name
-				}
+				for _, validationName := range model.Validations {
+
+					m, err := set.validationsMap.Mapping(typeMappingName, validationName)
+					if err != nil {
+						return nil, nil, fmt.Errorf("unable to apply validation mapping '%s' to model '%s': %s", validationName, model.RestName, err)
+					}
+					if m == nil {
+						continue
+					}
 
-				if typeConvertFunc != nil {
-					attr.ConvertedType, attr.TypeProvider = typeConvertFunc(attr.Type, attr.SubType)
+					model.ValidationProviders[m.providerKey()] = m
 				}
+			}
+		}
 
-				if typeMappingName != "" {
+		// massageAttribute applies the conversions and the mappings to
+		// the given attribute and to its nested properties.
+		var massageAttribute func(attr *Attribute) error
+		massageAttribute = func(attr *Attribute) error {
 
-					if set.typeMap != nil && attr.Type == AttributeTypeExt {
+			if err := attr.resolveEnum(set.enumsMap); err != nil {
+				return err
+			}
 
-						m, err := set.typeMap.Mapping(typeMappingName, attr.SubType)
-						if err != nil {
-							return nil, fmt.Errorf("unable to apply type mapping '%s' to attribute '%s'", attr.SubType, attr.Name)
-						}
+			if attr.ValidationProviders == nil {
+				attr.ValidationProviders = map[string]*ValidationMap{}
+			}
 
-						if m != nil {
-							attr.ConvertedType = m.Type
-							attr.Initializer = m.Initializer
-							attr.TypeProvider = m.Import
-						} else {
-							attr.ConvertedType = string(attr.Type)
-						}
+			if nameConvertFunc != nil {
+				attr.ConvertedName = nameConvertFunc(attr.Name)
+			} else {
+				attr.ConvertedName = attr.Name
+			}
+
+			if typeConvertFunc != nil {
+				attr.ConvertedType, attr.TypeProvider = typeConvertFunc(attr.Type, attr.SubType)
+			}
+
+			if typeMappingName != "" {
+
+				if set.typeMap != nil && attr.Type == AttributeTypeExt {
+
+					m, err := set.typeMap.Mapping(typeMappingName, attr.SubType)
+					if err != nil {
+						return fmt.Errorf("unable to apply type mapping '%s' to attribute '%s'", attr.SubType, attr.Name)
 					}
 
-					if set.validationsMap != nil {
+					if m != nil {
+						attr.ConvertedType = m.Type
+						attr.Initializer = m.Initializer
+						attr.TypeProvider = m.Import
+					} else {
+						attr.ConvertedType = string(attr.Type)
+					}
+				}
 
-						for _, validationName := range attr.Validations {
+				if set.validationsMap != nil {
 
-							m, err := set.validationsMap.Mapping(typeMappingName, validationName)
-							if err != nil {
-								return nil, fmt.Errorf("unable to apply validation mapping '%s' to attribute '%s': %s", validationName, attr.Name, err)
-							}
-							if m == nil {
-								continue
-							}
+					for _, validationName := range attr.Validations {
 
-							attr.ValidationProviders[m.Name] = m
+						m, err := set.validationsMap.Mapping(typeMappingName, validationName)
+						if err != nil {
+							return fmt.Errorf("unable to apply validation mapping '%s' to attribute '%s': %s", validationName, attr.Name, err)
+						}
+						if m == nil {
+							continue
 						}
+
+						attr.ValidationProviders[m.providerKey()] = m
 					}
 				}
 			}
+
+			for _, p := range attr.Properties {
+				if err := massageAttribute(p); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		for _, version := range spec.AttributeVersions() {
+			for _, attr := range spec.Attributes(version) {
+				if err := massageAttribute(attr); err != nil {
+					return nil, nil, err
+				}
+			}
 		}
 
 		if set.parametersMap != nil {
@@ -346,7 +442,7 @@ func LoadSpecificationSet(
 							spec.Model().Get.ParameterDefinition = &ParameterDefinition{}
 						}
 						if err := spec.Model().Get.ParameterDefinition.extend(set.parametersMap[key], key); err != nil {
-							return nil, err
+							return nil, nil, err
 						}
 					}
 				}
@@ -357,7 +453,7 @@ func LoadSpecificationSet(
 							spec.Model().Update.ParameterDefinition = &ParameterDefinition{}
 						}
 						if err := spec.Model().Update.ParameterDefinition.extend(set.parametersMap[key], key); err != nil {
-							return nil, err
+							return nil, nil, err
 						}
 					}
 				}
@@ -368,7 +464,7 @@ func LoadSpecificationSet(
 							spec.Model().Delete.ParameterDefinition = &ParameterDefinition{}
 						}
 						if err := spec.Model().Delete.ParameterDefinition.extend(set.parametersMap[key], key); err != nil {
-							return nil, err
+							return nil, nil, err
 						}
 					}
 				}
@@ -381,7 +477,7 @@ func LoadSpecificationSet(
 								r.Create.ParameterDefinition = &ParameterDefinition{}
 							}
 							if err := r.Create.ParameterDefinition.extend(set.parametersMap[key], key); err != nil {
-								return nil, err
+								return nil, nil, err
 							}
 						}
 					}
@@ -392,7 +488,7 @@ func LoadSpecificationSet(
 								r.Get.ParameterDefinition = &ParameterDefinition{}
 							}
 							if err := r.Get.ParameterDefinition.extend(set.parametersMap[key], key); err != nil {
-								return nil, err
+								return nil, nil, err
 							}
 						}
 					}
@@ -403,7 +499,7 @@ func LoadSpecificationSet(
 								r.Update.ParameterDefinition = &ParameterDefinition{}
 							}
 							if err := r.Update.ParameterDefinition.extend(set.parametersMap[key], key); err != nil {
-								return nil, err
+								return nil, nil, err
 							}
 						}
 					}
@@ -414,7 +510,7 @@ func LoadSpecificationSet(
 								r.Delete.ParameterDefinition = &ParameterDefinition{}
 							}
 							if err := r.Delete.ParameterDefinition.extend(set.parametersMap[key], key); err != nil {
-								return nil, err
+								return nil, nil, err
 							}
 						}
 					}
@@ -423,18 +519,81 @@ func LoadSpecificationSet(
 		}
 	}
 
+	// Resolve the enum values of the parameters.
+	for _, spec := range set.specs {
+
+		model := spec.Model()
+		if model == nil {
+			continue
+		}
+
+		actions := []*RelationAction{model.Get, model.Update, model.Delete}
+		for _, r := range spec.Relations() {
+			actions = append(actions, r.Create, r.Get, r.Update, r.Delete)
+		}
+
+		for _, a := range actions {
+
+			if a == nil {
+				continue
+			}
+
+			if err := a.ParameterDefinition.resolveEnums(set.enumsMap); err != nil {
+				return nil, nil, fmt.Errorf("%s.spec: %w", model.RestName, err)
+			}
+		}
+	}
+
+	if set.extensionsMap == nil {
+		set.extensionsMap = NewExtensionMapping()
+	}
+
 	var errs []error
 	for _, spec := range set.Specifications() {
 		if es := spec.Validate(); es != nil {
 			errs = append(errs, es...)
 		}
+		errs = append(errs, set.extensionsMap.checkSpecification(spec, set.strictExtensions)...)
+	}
+
+	return set, errs, nil
+}
+
+// specificationName returns the name of the specification
+// or the abstract stored in the file with the given name.
+func specificationName(fileName string) string {
+
+	baseName := strings.Replace(strings.Replace(fileName, ".spec", "", 1), ".abs", "", 1)
+
+	return strings.TrimPrefix(baseName, "+")
+}
+
+// loadSpecificationFile loads the .spec or .abs file with the given name
+// in the given folder, merged with the given overlays, and returns it with
+// its name.
+func loadSpecificationFile(dirname string, fileName string, overlays []string) (string, Specification, error) {
+
+	baseName := specificationName(fileName)
+
+	var (
+		spec Specification
+		err  error
+	)
+
+	if len(overlays) == 0 {
+		spec, err = LoadSpecification(path.Join(dirname, fileName), false)
+	} else {
+		spec, err = loadOverlaidSpecification(path.Join(dirname, fileName), overlays)
+	}
+	if err != nil {
+		return "", nil, err
 	}
 
-	if len(errs) > 0 {
-		return nil, formatValidationErrors(errs)
+	if spec.Model() != nil && spec.Model().RestName != baseName {
+		return "", nil, fmt.Errorf("%s: declared rest_name '%s' must be identical to filename without extension", fileName, spec.Model().RestName)
 	}
 
-	return set, nil
+	return baseName, spec, nil
 }
 
 func (s *specificationSet) Configuration() *Config {
@@ -457,6 +616,31 @@ func (s *specificationSet) APIInfo() *APIInfo {
 	return s.apiInfo
 }
 
+func (s *specificationSet) ParameterMapping() ParameterMapping {
+
+	return s.parametersMap
+}
+
+func (s *specificationSet) Abstracts() map[string]Specification {
+
+	return s.abstracts
+}
+
+func (s *specificationSet) EnumMapping() EnumMapping {
+
+	return s.enumsMap
+}
+
+func (s *specificationSet) ExtensionMapping() ExtensionMapping {
+
+	return s.extensionsMap
+}
+
+func (s *specificationSet) Commit() string {
+
+	return s.commit
+}
+
 // Specification returns the Specification with the given name.
 func (s *specificationSet) Specification(name string) Specification {
 
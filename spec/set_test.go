@@ -12,6 +12,7 @@
 package spec
 
 import (
+	"os"
 	"strings"
 	"testing"
 
@@ -811,9 +812,96 @@ func TestSpec_LoadSpecificationDir(t *testing.T) {
 			So(m.Name, ShouldEqual, "validate.CheckUserName")
 		})
 
+		Convey("Then the model validations should be resolved", func() {
+			vp := set.Specification("user").Model().ValidationProviders
+			So(len(vp), ShouldEqual, 1)
+			So(vp["validate.CheckUserName"].Name, ShouldEqual, "validate.CheckUserName")
+			So(set.Specification("task").Model().ValidationProviders, ShouldBeEmpty)
+		})
+
 		Convey("Then the api info should be correctly loaded", func() {
 
 			So(set.APIInfo().Version, ShouldEqual, 1)
 		})
 	})
 }
+
+func TestSpec_LoadSpecificationDirModelValidations(t *testing.T) {
+
+	Convey("Given I have a spec folder with an unknown model validation", t, func() {
+
+		data, _ := os.ReadFile("./tests/user.spec")
+
+		dir := copyTestFolder(t, map[string]string{
+			"user.spec": strings.Replace(string(data), "  - $username\n", "  - $usrename\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "test")
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "unable to apply validation mapping '$usrename' to model 'user': no function '$usrename' found in type mapping mode test")
+			})
+		})
+
+		Convey("When I load it without mapping mode", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "")
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "unable to apply validation mapping '$usrename' to model 'user': no function '$usrename' found")
+			})
+		})
+	})
+}
+
+func TestSpec_LoadSpecificationDirValidationArguments(t *testing.T) {
+
+	mapping, _ := os.ReadFile("./tests/_validation.mapping")
+	task, _ := os.ReadFile("./tests/task.spec")
+
+	Convey("Given I have a spec folder using validations with arguments", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"_validation.mapping": string(mapping) + "\n" + parameterizedValidationMapping,
+			"task.spec":           strings.Replace(string(task), "    - $nocap\n", "    - $nocap\n    - $oneOfPrefix(false, \"a\", \"b\")\n    - $oneOfPrefix(true, \"c\")\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			set, err := LoadSpecificationSet(dir, nil, nil, "test")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the arguments should be resolved", func() {
+				vp := set.Specification("task").Attribute("name", "v1").ValidationProviders
+				So(vp[`validate.OneOfPrefix(false, "a", "b")`].Arguments, ShouldResemble, []any{false, "a", "b"})
+				So(vp[`validate.OneOfPrefix(true, "c")`].Arguments, ShouldResemble, []any{true, "c"})
+				So(vp["nocapper.NoCap"], ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given I have a spec folder using validations with invalid arguments", t, func() {
+
+		dir := copyTestFolder(t, map[string]string{
+			"_validation.mapping": string(mapping) + "\n" + parameterizedValidationMapping,
+			"task.spec":           strings.Replace(string(task), "    - $nocap\n", "    - $nocap\n    - $range(1)\n", 1),
+		})
+
+		Convey("When I load it", func() {
+
+			_, err := LoadSpecificationSet(dir, nil, nil, "test")
+
+			Convey("Then err should not be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "unable to apply validation mapping '$range(1)' to attribute 'name': invalid arguments for function '$range' in mode test: expected 2 argument(s), got 1")
+			})
+		})
+	})
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// A Source provides the folder containing a specification set.
+type Source interface {
+
+	// Fetch makes the specifications available in a local folder.
+	// The Release function of the returned FetchResult must be called
+	// once the specifications are loaded. Fetch must stop as soon as
+	// possible when ctx is canceled.
+	Fetch(ctx context.Context, opts FetchOptions) (*FetchResult, error)
+}
+
+// A FetchResult is returned by Source.Fetch.
+type FetchResult struct {
+
+	// Path is the path of the folder containing the specifications.
+	Path string
+
+	// Commit is the hash of the commit the specifications
+	// come from, if the Source is versioned.
+	Commit string
+
+	// Release releases the resources used by the fetch.
+	Release func()
+}
+
+// FetchOptions holds the options given to Source.Fetch.
+type FetchOptions struct {
+
+	// Logger is the logger to use. It is never nil.
+	Logger *slog.Logger
+
+	// Progress is an optional function called with
+	// each progress message of the fetch.
+	Progress func(message string)
+}
+
+// progress calls the Progress function if it is set.
+func (o FetchOptions) progress(format string, args ...any) {
+
+	if o.Progress != nil {
+		o.Progress(fmt.Sprintf(format, args...))
+	}
+}
+
+// progressWriter returns an io.Writer calling the Progress function
+// for each line written, or nil if the Progress function is not set.
+// Lines can be terminated by either '\n' or '\r', as git servers
+// use the latter to update the current progress line.
+func (o FetchOptions) progressWriter() io.Writer {
+
+	if o.Progress == nil {
+		return nil
+	}
+
+	return &lineWriter{f: o.Progress}
+}
+
+type lineWriter struct {
+	f   func(string)
+	buf []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+
+	for _, c := range p {
+
+		if c != '\n' && c != '\r' {
+			w.buf = append(w.buf, c)
+			continue
+		}
+
+		if line := strings.TrimSpace(string(w.buf)); line != "" {
+			w.f(line)
+		}
+		w.buf = w.buf[:0]
+	}
+
+	return len(p), nil
+}
+
+type directorySource struct {
+	dirname string
+}
+
+// NewDirectorySource returns a Source reading the
+// specifications from the given local folder.
+func NewDirectorySource(dirname string) Source {
+	return &directorySource{
+		dirname: dirname,
+	}
+}
+
+func (s *directorySource) Fetch(ctx context.Context, opts FetchOptions) (*FetchResult, error) {
+	return &FetchResult{
+		Path:    s.dirname,
+		Release: func() {},
+	}, nil
+}
+
+// NewGithubSource returns a Source cloning the given github repository
+// at the given branch, tag or commit, and reading the specifications from
+// the given internal path. The token is optional. It is a shortcut for
+// NewGitSource using the token as HTTP bearer authentication.
+func NewGithubSource(token string, repoURL string, refName string, internalPath string) Source {
+
+	var opts []GitSourceOption
+	if token != "" {
+		opts = append(opts, GitOptionToken(token))
+	}
+
+	return NewGitSource(repoURL, refName, internalPath, opts...)
+}
@@ -0,0 +1,483 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// makeTestRepository creates a local bare repository containing the test
+// specifications. The first commit is tagged v1.0.0 and the second one,
+// at the head of master, changes the description of the task model.
+// It returns the path of the repository and the hash of the first commit.
+func makeTestRepository(t *testing.T) (string, plumbing.Hash) {
+
+	dir := copyTestFolder(t, nil)
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := func(msg string) plumbing.Hash {
+
+		if _, err := wt.Add("."); err != nil {
+			t.Fatal(err)
+		}
+
+		h, err := wt.Commit(msg, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return h
+	}
+
+	first := commit("first")
+
+	if _, err := repo.CreateTag("v1.0.0", first, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := os.ReadFile(path.Join(dir, "task.spec"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task = bytes.Replace(task, []byte("Represent a task to do in a listd."), []byte("Represent a task."), 1)
+	if err := os.WriteFile(path.Join(dir, "task.spec"), task, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	commit("second")
+
+	bare := path.Join(t.TempDir(), "specs.git")
+	if _, err := git.PlainClone(bare, true, &git.CloneOptions{URL: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	return bare, first
+}
+
+func TestSource_GithubSource(t *testing.T) {
+
+	repo, first := makeTestRepository(t)
+
+	Convey("Given I load a set from a branch of a repository", t, func() {
+
+		var messages []string
+		set, err := Load(
+			context.Background(),
+			NewGithubSource("", repo, "master", ""),
+			OptionProgress(func(msg string) { messages = append(messages, msg) }),
+		)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the set should be loaded from the head of the branch", func() {
+			So(set.Specification("task").Model().Description, ShouldEqual, "Represent a task.")
+		})
+
+		Convey("Then the progress should be reported", func() {
+			So(messages, ShouldNotBeEmpty)
+			So(messages[0], ShouldEqual, "Fetching "+repo)
+		})
+	})
+
+	Convey("Given I load a set from a tag of a repository", t, func() {
+
+		set, err := Load(context.Background(), NewGithubSource("", repo, "v1.0.0", ""))
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the set should be loaded from the tag", func() {
+			So(set.Specification("task").Model().Description, ShouldEqual, "Represent a task to do in a listd.")
+		})
+	})
+
+	Convey("Given I load a set from a commit of a repository", t, func() {
+
+		var messages []string
+		set, err := Load(
+			context.Background(),
+			NewGithubSource("", repo, first.String(), ""),
+			OptionProgress(func(msg string) { messages = append(messages, msg) }),
+		)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the set should be loaded from the commit", func() {
+			So(set.Specification("task").Model().Description, ShouldEqual, "Represent a task to do in a listd.")
+		})
+
+		Convey("Then the extraction should be reported", func() {
+			So(messages[len(messages)-1], ShouldEqual, "Extracting "+first.String())
+		})
+
+		Convey("Then the commit should be recorded", func() {
+			So(set.(CommitProvider).Commit(), ShouldEqual, first.String())
+		})
+	})
+
+	Convey("Given I load a set from a repository with a canceled context", t, func() {
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Load(ctx, NewGithubSource("", repo, "master", ""))
+
+		Convey("Then err should be correct", func() {
+			So(err, ShouldEqual, context.Canceled)
+		})
+	})
+
+	Convey("Given I load a set from a missing reference of a repository", t, func() {
+
+		_, err := Load(context.Background(), NewGithubSource("", repo, "nope", ""))
+
+		Convey("Then err should not be nil", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to find reference 'nope'")
+		})
+	})
+}
+
+func TestSource_GitSource(t *testing.T) {
+
+	repo, first := makeTestRepository(t)
+
+	Convey("Given I load a set from an abbreviated commit using a file URL", t, func() {
+
+		set, err := Load(context.Background(), NewGitSource("file://"+repo, first.String()[:7], ""))
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the set should be loaded from the commit", func() {
+			So(set.Specification("task").Model().Description, ShouldEqual, "Represent a task to do in a listd.")
+			So(set.(CommitProvider).Commit(), ShouldEqual, first.String())
+		})
+	})
+
+	Convey("Given I load a set from a directory loaded from a folder", t, func() {
+
+		set, err := Load(context.Background(), NewDirectorySource("./tests"))
+
+		Convey("Then the commit should be empty", func() {
+			So(err, ShouldBeNil)
+			So(set.(CommitProvider).Commit(), ShouldEqual, "")
+		})
+	})
+
+	Convey("Given I load a set without reference", t, func() {
+
+		_, err := Load(context.Background(), NewGitSource(repo, "", ""))
+
+		Convey("Then err should be correct", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to fetch '"+repo+"': a reference is required")
+		})
+	})
+
+	Convey("Given I load a set from an internal path that does not exist", t, func() {
+
+		_, err := Load(context.Background(), NewGitSource(repo, "master", "specs"))
+
+		Convey("Then err should be correct", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to find path 'specs': directory not found")
+		})
+	})
+
+	Convey("Given I load a set with an ssh key that does not exist", t, func() {
+
+		_, err := Load(context.Background(), NewGitSource("git@example.com:org/specs.git", "master", "", GitOptionSSHKey("/not/a/key", "")))
+
+		Convey("Then err should be correct", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "unable to load ssh key '/not/a/key': ")
+		})
+	})
+
+	Convey("Given I have a cache folder", t, func() {
+
+		cache := t.TempDir()
+
+		fetches := func(ref string) (SpecificationSet, int) {
+
+			var n int
+			set, err := Load(
+				context.Background(),
+				NewGitSource(repo, ref, "", GitOptionCacheDir(cache)),
+				OptionProgress(func(msg string) {
+					if strings.HasPrefix(msg, "Fetching ") {
+						n++
+					}
+				}),
+			)
+			So(err, ShouldBeNil)
+
+			return set, n
+		}
+
+		Convey("When I load a tag twice", func() {
+
+			set1, n1 := fetches("v1.0.0")
+			set2, n2 := fetches("v1.0.0")
+
+			Convey("Then the repository should only be fetched once", func() {
+				So(n1, ShouldEqual, 1)
+				So(n2, ShouldEqual, 0)
+				So(set1.(CommitProvider).Commit(), ShouldEqual, first.String())
+				So(set2.(CommitProvider).Commit(), ShouldEqual, first.String())
+			})
+
+			Convey("Then the repository should be cached by URL", func() {
+				entries, _ := os.ReadDir(cache)
+				So(len(entries), ShouldEqual, 1)
+			})
+
+			Convey("When I load a commit", func() {
+
+				set, n := fetches(first.String())
+
+				Convey("Then the cached commit should be used", func() {
+					So(n, ShouldEqual, 0)
+					So(set.(CommitProvider).Commit(), ShouldEqual, first.String())
+				})
+			})
+
+			Convey("When I load an abbreviated commit", func() {
+
+				set, n := fetches(first.String()[:10])
+
+				Convey("Then the repository should be fetched again", func() {
+					So(n, ShouldEqual, 1)
+					So(set.(CommitProvider).Commit(), ShouldEqual, first.String())
+				})
+			})
+
+			Convey("When I load a branch", func() {
+
+				set, n := fetches("master")
+
+				Convey("Then the repository should be fetched again", func() {
+					So(n, ShouldEqual, 1)
+					So(set.Specification("task").Model().Description, ShouldEqual, "Represent a task.")
+				})
+			})
+		})
+	})
+}
+
+func TestSource_GitSourceHexBranch(t *testing.T) {
+
+	repo, first := makeTestRepository(t)
+
+	r, err := git.PlainOpen(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := r.Reference(plumbing.NewBranchReferenceName("master"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The name of the branch is also the abbreviated
+	// hash of the first commit, which is in the cache.
+	branch := first.String()[:10]
+	if err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), head.Hash())); err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Given I have a cache folder containing all the commits", t, func() {
+
+		cache := t.TempDir()
+
+		_, err := Load(context.Background(), NewGitSource(repo, "v1.0.0", "", GitOptionCacheDir(cache)))
+		So(err, ShouldBeNil)
+
+		Convey("When I load a branch whose name looks like a cached commit", func() {
+
+			set, err := Load(context.Background(), NewGitSource(repo, branch, "", GitOptionCacheDir(cache)))
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the branch should be used", func() {
+				So(set.(CommitProvider).Commit(), ShouldEqual, head.Hash().String())
+			})
+		})
+	})
+}
+
+func TestSource_lockRepository(t *testing.T) {
+
+	Convey("Given I lock a cached repository", t, func() {
+
+		repoDir := filepath.Join(t.TempDir(), "repo")
+
+		unlock, err := lockRepository(context.Background(), repoDir)
+		So(err, ShouldBeNil)
+
+		Convey("When I lock it again before it is released", func() {
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+
+			_, err := lockRepository(ctx, repoDir)
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+			})
+		})
+
+		Convey("When I lock it again after it is released", func() {
+
+			unlock()
+
+			unlock, err := lockRepository(context.Background(), repoDir)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+				unlock()
+			})
+		})
+
+		Convey("When I lock it again while the lock is stale", func() {
+
+			stale := time.Now().Add(-2 * repositoryLockTimeout)
+			So(os.Chtimes(repoDir+".lock", stale, stale), ShouldBeNil)
+
+			unlock, err := lockRepository(context.Background(), repoDir)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+				unlock()
+			})
+		})
+	})
+}
+
+func TestSource_isCommitHash(t *testing.T) {
+
+	Convey("Given I have some strings", t, func() {
+		So(isCommitHash("abc"), ShouldBeFalse)
+		So(isCommitHash("abcd"), ShouldBeTrue)
+		So(isCommitHash("abcde"), ShouldBeTrue)
+		So(isCommitHash("ABCDEF0123"), ShouldBeTrue)
+		So(isCommitHash("master"), ShouldBeFalse)
+		So(isCommitHash(strings.Repeat("a", 41)), ShouldBeFalse)
+	})
+}
+
+func TestSource_lineWriter(t *testing.T) {
+
+	Convey("Given I have a lineWriter", t, func() {
+
+		var lines []string
+		w := &lineWriter{f: func(l string) { lines = append(lines, l) }}
+
+		Convey("When I write progress lines", func() {
+
+			_, _ = w.Write([]byte("Counting objects: 1\rCounting objects: 2\r"))
+			_, _ = w.Write([]byte("Counting objects: 3, done.\n\nCompress"))
+			_, _ = w.Write([]byte("ing\n"))
+
+			Convey("Then the lines should be correct", func() {
+				So(lines, ShouldResemble, []string{
+					"Counting objects: 1",
+					"Counting objects: 2",
+					"Counting objects: 3, done.",
+					"Compressing",
+				})
+			})
+		})
+	})
+}
+
+func TestSource_ExtractRevision(t *testing.T) {
+
+	bare, first := makeTestRepository(t)
+
+	repo := path.Join(t.TempDir(), "specs")
+	if _, err := git.PlainClone(repo, false, &git.CloneOptions{URL: bare}); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions := map[string]string{
+		"master":                            "Represent a task.",
+		"v1.0.0":                            "Represent a task to do in a listd.",
+		first.String():                      "Represent a task to do in a listd.",
+		first.String()[:7]:                  "Represent a task to do in a listd.",
+		strings.ToUpper(first.String()[:7]): "Represent a task to do in a listd.",
+	}
+
+	for revision, description := range revisions {
+
+		Convey("Given I extract the revision '"+revision+"' of a repository", t, func() {
+
+			dest := t.TempDir()
+			err := ExtractRevision(repo, revision, "", dest)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the extracted set should be the one of the revision", func() {
+				set, err := LoadSpecificationSet(dest, nil, nil, "")
+				So(err, ShouldBeNil)
+				So(set.Specification("task").Model().Description, ShouldEqual, description)
+			})
+		})
+	}
+
+	Convey("Given I extract an unknown abbreviated commit hash", t, func() {
+
+		err := ExtractRevision(repo, "0000000", "", t.TempDir())
+
+		Convey("Then err should not be nil", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to find commit '0000000'")
+		})
+	})
+}
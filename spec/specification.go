@@ -45,16 +45,39 @@ type specification struct {
 	RawIndexes      [][]string          `yaml:"indexes,omitempty"       json:"indexes,omitempty"`
 	RawDefaultOrder []string            `yaml:"default_order,omitempty" json:"default_order,omitempty"`
 
-	attributeMap attributeMapping
-	relationsMap relationMapping
-	identifier   *Attribute
-	path         string
+	attributeMap      attributeMapping
+	relationsMap      relationMapping
+	identifier        *Attribute
+	path              string
+	attributeIncludes map[string][]listInclude
+	relationIncludes  []listInclude
+	preserveOrder     bool
+}
+
+// A SpecificationOption configures a Specification
+// returned by NewSpecification.
+type SpecificationOption func(*specification)
+
+// SpecificationOptionPreserveOrder makes the specification keep the
+// declared order of its attributes, relations and parameters, as if
+// its model had preserve_order set. Unlike the model setting, it also
+// applies to abstracts.
+func SpecificationOptionPreserveOrder() SpecificationOption {
+	return func(s *specification) {
+		s.preserveOrder = true
+	}
 }
 
 // NewSpecification returns a new specification.
-func NewSpecification() Specification {
+func NewSpecification(opts ...SpecificationOption) Specification {
 
-	return &specification{}
+	s := &specification{}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // LoadSpecification returns a new specification using the given file path.
@@ -104,16 +127,36 @@ func massageYAML(in any) any {
 	return out
 }
 
-// Read loads a specifaction from the given io.Reader
+// Read loads a specifaction from the given io.Reader.
+// The attribute and relation lists can contain include items
+// like "- include: file.yaml". If the specification has been
+// loaded from a file, they are replaced by the items listed in the
+// given file, relative to the specification. Otherwise, they are
+// only kept to be written back.
 func (s *specification) Read(reader io.Reader, validate bool) (err error) {
 
-	decoder := yaml.NewDecoder(reader)
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("unable to read spec: %s", err)
+	}
+
+	if data, err = s.extractIncludes(data); err != nil {
+		return fmt.Errorf("unable to extract includes: %s", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
 	decoder.SetStrict(true)
 
 	if err = decoder.Decode(s); err != nil {
 		return fmt.Errorf("unable to decode spec yaml: %s", err)
 	}
 
+	if s.path != "" {
+		if err = s.resolveIncludes(); err != nil {
+			return err
+		}
+	}
+
 	for _, attrs := range s.RawAttributes {
 		for _, attr := range attrs {
 			if attr.ExampleValue != nil {
@@ -133,7 +176,7 @@ func (s *specification) Read(reader io.Reader, validate bool) (err error) {
 		return fmt.Errorf("unable to build relations mapping: %s", err)
 	}
 
-	if s.RawModel != nil {
+	if s.RawModel != nil && s.RawModel.EntityNamePlural == "" {
 		s.RawModel.EntityNamePlural = Pluralize(s.RawModel.EntityName)
 	}
 
@@ -147,12 +190,22 @@ func (s *specification) Read(reader io.Reader, validate bool) (err error) {
 }
 
 // Write dumps the specification into a []byte.
+// The attributes, relations and parameters are sorted by
+// name unless the specification preserves their order.
 func (s *specification) Write(writer io.Writer) error {
 
+	preserveOrder := s.preservesOrder()
 	repr := yaml.MapSlice{}
 
 	if s.RawModel != nil {
-		repr = append(repr, yaml.MapItem{Key: rootModelKey, Value: toYAMLMapSlice(s.RawModel)})
+
+		// The computed plural is not written back, only explicit overrides are.
+		model := *s.RawModel
+		if model.EntityNamePlural == Pluralize(model.EntityName) {
+			model.EntityNamePlural = ""
+		}
+
+		repr = append(repr, yaml.MapItem{Key: rootModelKey, Value: toYAMLMapSlice(&model)})
 	}
 
 	if len(s.RawDefaultOrder) != 0 {
@@ -170,64 +223,78 @@ func (s *specification) Write(writer io.Writer) error {
 		for _, version := range sortVersionStrings(s.AttributeVersions()) {
 
 			currentAttributes := s.RawAttributes[version]
-			sortAttributes(currentAttributes)
+			if !preserveOrder {
+				sortAttributes(currentAttributes)
+			}
 
-			attrs := make([]yaml.MapSlice, len(currentAttributes))
+			// Included attributes are written as their include item.
+			var attrs []yaml.MapSlice
+			for _, attr := range currentAttributes {
+				if attr.includedFrom == "" {
+					attrs = append(attrs, attributeToYAMLMapSlice(attr))
+				}
+			}
+			attrs = withIncludeItems(attrs, s.attributeIncludes[version], preserveOrder)
 
 			versionedAttrs = append(versionedAttrs, yaml.MapItem{
 				Key:   version,
 				Value: attrs,
 			})
-
-			for i, attr := range currentAttributes {
-				attrs[i] = toYAMLMapSlice(attr)
-			}
 		}
 
 		repr = append(repr, yaml.MapItem{Key: rootAttributesKey, Value: versionedAttrs})
 	}
 
-	if len(s.RawRelations) != 0 {
+	if len(s.RawRelations) != 0 || len(s.relationIncludes) != 0 {
 
-		relations := make([]yaml.MapSlice, len(s.RawRelations))
+		var relations []yaml.MapSlice
 
 		rawRelations := s.RawRelations[:]
-		sort.Slice(rawRelations, func(i int, j int) bool {
-			return strings.Compare(rawRelations[i].RestName, rawRelations[j].RestName) == -1
-		})
+		if !preserveOrder {
+			sort.Slice(rawRelations, func(i int, j int) bool {
+				return strings.Compare(rawRelations[i].RestName, rawRelations[j].RestName) == -1
+			})
+		}
+
+		for _, rel := range rawRelations {
+
+			if rel.includedFrom != "" {
+				continue
+			}
 
-		for i, rel := range rawRelations {
 			if rel.Get != nil {
 				rel.Get.Description = wordwrap.WrapString(rel.Get.Description, 80)
-				if rel.Get.ParameterDefinition != nil {
+				if rel.Get.ParameterDefinition != nil && !preserveOrder {
 					sortParameters(rel.Get.ParameterDefinition.Entries)
 				}
 			}
 
 			if rel.Create != nil {
 				rel.Create.Description = wordwrap.WrapString(rel.Create.Description, 80)
-				if rel.Create.ParameterDefinition != nil {
+				if rel.Create.ParameterDefinition != nil && !preserveOrder {
 					sortParameters(rel.Create.ParameterDefinition.Entries)
 				}
 			}
 
 			if rel.Update != nil {
 				rel.Update.Description = wordwrap.WrapString(rel.Update.Description, 80)
-				if rel.Update.ParameterDefinition != nil {
+				if rel.Update.ParameterDefinition != nil && !preserveOrder {
 					sortParameters(rel.Update.ParameterDefinition.Entries)
 				}
 			}
 
 			if rel.Delete != nil {
 				rel.Delete.Description = wordwrap.WrapString(rel.Delete.Description, 80)
-				if rel.Delete.ParameterDefinition != nil {
+				if rel.Delete.ParameterDefinition != nil && !preserveOrder {
 					sortParameters(rel.Delete.ParameterDefinition.Entries)
 				}
 			}
 
-			relations[i] = toYAMLMapSlice(rel)
+			relations = append(relations, toYAMLMapSlice(rel))
 		}
 
+		relations = withIncludeItems(relations, s.relationIncludes, preserveOrder)
+
 		repr = append(repr, yaml.MapItem{Key: rootRelationsKey, Value: relations})
 	}
 
@@ -242,6 +309,7 @@ func (s *specification) Write(writer io.Writer) error {
 	prfx2 := []byte("  - name")
 	prfx3 := []byte("  v")
 	prfx4 := []byte("      - name")
+	prfx5 := []byte("  - include")
 	sufx1 := []byte(":")
 	yamlModelKey := []byte(rootModelKey + ":")
 	yamlDefaultOrderKey := []byte(rootDefaultOrderKey + ":")
@@ -267,7 +335,8 @@ func (s *specification) Write(writer io.Writer) error {
 		condPrefixed := bytes.HasPrefix(line, prfx1) ||
 			(bytes.HasPrefix(line, prfx3) && !bytes.HasPrefix(line, []byte("  validation"))) ||
 			(bytes.HasPrefix(line, prfx2) && !bytes.HasSuffix(previousLine, sufx1)) ||
-			(bytes.HasPrefix(line, prfx4) && !bytes.HasSuffix(previousLine, sufx1))
+			(bytes.HasPrefix(line, prfx4) && !bytes.HasSuffix(previousLine, sufx1)) ||
+			(bytes.HasPrefix(line, prfx5) && !bytes.HasSuffix(previousLine, sufx1))
 
 		if !condFirstLine && !condFirstIn && !inIndexes && condPrefixed {
 			_, _ = buf.WriteRune('\n')
@@ -370,6 +439,14 @@ func (s *specification) Validate() []error {
 		}
 	}
 
+	// Relation actions and parameters do not know about the model
+	// they belong to, so we link it for lint suppressions to work.
+	for _, err := range errs {
+		if lerr, ok := err.(*LintError); ok && lerr.Model == nil && lerr.Attribute == nil {
+			lerr.Model = s.RawModel
+		}
+	}
+
 	return errs
 }
 
@@ -441,6 +518,7 @@ func (s *specification) LatestAttributesVersion() string {
 		}
 
 		if vi >= max {
+			max = vi
 			latest = v
 		}
 	}
@@ -448,26 +526,35 @@ func (s *specification) LatestAttributesVersion() string {
 	return latest
 }
 
-// Attributes returns the list of attribute sorted by names.
+// Attributes returns the list of attribute sorted by names, or in the
+// declared order if the specification preserves it. In that case, the
+// attributes inherited from the extended abstracts come after the ones
+// the specification declares in the same version, in the order of the
+// extends list.
 func (s *specification) Attributes(version string) []*Attribute {
 
-	attrMap := map[string]*Attribute{}
+	attrs := []*Attribute{}
+	indexes := map[string]int{}
 
+	// Attributes redefined in a later version
+	// keep the position of their first declaration.
 	for _, v := range s.versionsFrom(version) {
 		for _, attr := range s.RawAttributes[v] {
-			attrMap[attr.Name] = attr
+
+			if i, ok := indexes[attr.Name]; ok {
+				attrs[i] = attr
+				continue
+			}
+
+			indexes[attr.Name] = len(attrs)
+			attrs = append(attrs, attr)
 		}
 	}
 
-	attrs := make([]*Attribute, len(attrMap))
-	var i int
-	for _, attr := range attrMap {
-		attrs[i] = attr
-		i++
+	if !s.preservesOrder() {
+		sortAttributes(attrs)
 	}
 
-	sortAttributes(attrs)
-
 	return attrs
 }
 
@@ -578,30 +665,38 @@ func (s *specification) TypeProviders() []string {
 	return providers
 }
 
-// ValidationProviders returns the unique list of all attributes validation providers.
+// ValidationProviders returns the unique list of all model and attributes validation providers.
 func (s *specification) ValidationProviders() []string {
 
 	yes := &struct{}{}
 	cache := map[string]*struct{}{}
 	var providers []string
 
-	for _, attrs := range s.RawAttributes {
-		for _, attr := range attrs {
+	add := func(validationProviders map[string]*ValidationMap) {
 
-			for _, m := range attr.ValidationProviders {
+		for _, m := range validationProviders {
 
-				if _, ok := cache[m.Import]; ok {
-					continue
-				}
+			if _, ok := cache[m.Import]; ok {
+				continue
+			}
 
-				cache[m.Import] = yes
-				if m.Import != "" {
-					providers = append(providers, m.Import)
-				}
+			cache[m.Import] = yes
+			if m.Import != "" {
+				providers = append(providers, m.Import)
 			}
 		}
 	}
 
+	if s.RawModel != nil {
+		add(s.RawModel.ValidationProviders)
+	}
+
+	for _, attrs := range s.RawAttributes {
+		for _, attr := range attrs {
+			add(attr.ValidationProviders)
+		}
+	}
+
 	sort.Strings(providers)
 	return providers
 }
@@ -620,6 +715,7 @@ func (s *specification) buildAttributesMapping() error {
 		for _, attr := range attrs {
 
 			attr.linkedSpecification = s
+			attr.linkProperties()
 
 			if _, ok := s.attributeMap[version][attr.Name]; ok {
 				if s.RawModel != nil {
@@ -695,3 +791,9 @@ func (s *specification) versionsFrom(version string) []string {
 
 	return out
 }
+
+// preservesOrder returns true if the declared order of the
+// attributes, relations and parameters must be kept.
+func (s *specification) preservesOrder() bool {
+	return s.preserveOrder || (s.RawModel != nil && s.RawModel.PreserveOrder)
+}
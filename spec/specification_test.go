@@ -13,6 +13,9 @@ package spec
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -97,7 +100,7 @@ func TestSpecification_Validate(t *testing.T) {
 				So(errs, ShouldNotBeNil)
 				So(formatValidationErrors(errs).Error(), ShouldEqual, `thing.spec: schema error: attributes.v1.0: description is required
 thing.spec: schema error: attributes.v1.0: type is required
-thing.spec: schema error: attributes.v1.1.type: attributes.v1.1.type must be one of the following: "string", "integer", "float", "boolean", "enum", "list", "object", "time", "external", "ref", "refList", "refMap"
+thing.spec: schema error: attributes.v1.1.type: attributes.v1.1.type must be one of the following: "string", "integer", "float", "boolean", "enum", "list", "map", "object", "time", "external", "ref", "refList", "refMap"
 thing.spec: schema error: model: package is required`)
 			})
 		})
@@ -191,6 +194,24 @@ func TestSpecification_Getters(t *testing.T) {
 			So(s.Identifier().Name, ShouldEqual, "id")
 		})
 	})
+
+	Convey("Given I have a specification with several attribute versions", t, func() {
+
+		s := &specification{
+			RawAttributes: map[string][]*Attribute{
+				"v1":  {},
+				"v12": {},
+				"v2":  {},
+				"v3":  {},
+			},
+		}
+
+		Convey("Then the latest version should be correct", func() {
+			for i := 0; i < 20; i++ {
+				So(s.LatestAttributesVersion(), ShouldEqual, "v12")
+			}
+		})
+	})
 }
 
 func TestSpecification_TypeProviders(t *testing.T) {
@@ -280,6 +301,42 @@ func TestSpecification_AttributesProviders(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given I have a new API with model validations", t, func() {
+
+		s := &specification{
+			RawModel: &Model{
+				ValidationProviders: map[string]*ValidationMap{
+					"e": {
+						Import: "e",
+					},
+					"a": {
+						Import: "a",
+					},
+				},
+			},
+			RawAttributes: map[string][]*Attribute{
+				"v1": {
+					{
+						ValidationProviders: map[string]*ValidationMap{
+							"a": {
+								Import: "a",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		Convey("When I call ValidationProviders", func() {
+
+			providers := s.ValidationProviders()
+
+			Convey("Then the providers should be correct", func() {
+				So(providers, ShouldResemble, []string{"a", "e"})
+			})
+		})
+	})
 }
 
 func TestSpecification_AttributeMap(t *testing.T) {
@@ -483,10 +540,10 @@ func TestSpecification_LoadSpecification(t *testing.T) {
 			So(attrs[0].ForeignKey, ShouldBeFalse)
 			So(attrs[0].Getter, ShouldBeFalse)
 			So(attrs[0].Identifier, ShouldBeFalse)
-			So(attrs[0].MaxLength, ShouldEqual, 0)
-			So(attrs[0].MaxValue, ShouldEqual, 0.0)
-			So(attrs[0].MinLength, ShouldEqual, 0)
-			So(attrs[0].MinValue, ShouldEqual, 0.0)
+			So(attrs[0].MaxLength, ShouldBeNil)
+			So(attrs[0].MaxValue, ShouldBeNil)
+			So(attrs[0].MinLength, ShouldBeNil)
+			So(attrs[0].MinValue, ShouldBeNil)
 			So(attrs[0].Name, ShouldEqual, "description")
 			So(attrs[0].Orderable, ShouldBeTrue)
 			So(attrs[0].PrimaryKey, ShouldBeFalse)
@@ -514,10 +571,10 @@ func TestSpecification_LoadSpecification(t *testing.T) {
 			So(attrs[1].ForeignKey, ShouldBeFalse)
 			So(attrs[1].Getter, ShouldBeTrue)
 			So(attrs[1].Identifier, ShouldBeFalse)
-			So(attrs[1].MaxLength, ShouldEqual, 0)
-			So(attrs[1].MaxValue, ShouldEqual, 0.0)
-			So(attrs[1].MinLength, ShouldEqual, 0)
-			So(attrs[1].MinValue, ShouldEqual, 0.0)
+			So(attrs[1].MaxLength, ShouldBeNil)
+			So(attrs[1].MaxValue, ShouldBeNil)
+			So(attrs[1].MinLength, ShouldBeNil)
+			So(attrs[1].MinValue, ShouldBeNil)
 			So(attrs[1].Name, ShouldEqual, "name")
 			So(attrs[1].Orderable, ShouldBeTrue)
 			So(attrs[1].PrimaryKey, ShouldBeFalse)
@@ -545,10 +602,10 @@ func TestSpecification_LoadSpecification(t *testing.T) {
 			So(attrs[2].ForeignKey, ShouldBeFalse)
 			So(attrs[2].Getter, ShouldBeFalse)
 			So(attrs[2].Identifier, ShouldBeFalse)
-			So(attrs[2].MaxLength, ShouldEqual, 0)
-			So(attrs[2].MaxValue, ShouldEqual, 0.0)
-			So(attrs[2].MinLength, ShouldEqual, 0)
-			So(attrs[2].MinValue, ShouldEqual, 0.0)
+			So(attrs[2].MaxLength, ShouldBeNil)
+			So(attrs[2].MaxValue, ShouldBeNil)
+			So(attrs[2].MinLength, ShouldBeNil)
+			So(attrs[2].MinValue, ShouldBeNil)
 			So(attrs[2].Name, ShouldEqual, "status")
 			So(attrs[2].Orderable, ShouldBeTrue)
 			So(attrs[2].PrimaryKey, ShouldBeFalse)
@@ -1003,3 +1060,281 @@ relations:
 		})
 	})
 }
+
+func TestSpecification_EntityNamePlural(t *testing.T) {
+
+	Convey("Given I read a specification without entity_name_plural", t, func() {
+
+		spec := NewSpecification()
+		err := spec.Read(strings.NewReader(`model:
+  rest_name: person
+  resource_name: people
+  entity_name: Person
+  package: todo-list
+  group: core
+  description: A person.
+`), true)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the plural should be computed", func() {
+			So(spec.Model().EntityNamePlural, ShouldEqual, "People")
+		})
+
+		Convey("When I write it", func() {
+
+			buf := bytes.NewBuffer(nil)
+			_ = spec.Write(buf)
+
+			Convey("Then the computed plural should not be written", func() {
+				So(buf.String(), ShouldNotContainSubstring, "entity_name_plural")
+			})
+		})
+	})
+
+	Convey("Given I read a specification with entity_name_plural", t, func() {
+
+		spec := NewSpecification()
+		err := spec.Read(strings.NewReader(`model:
+  rest_name: sheep
+  resource_name: sheep
+  entity_name: Sheep
+  entity_name_plural: Sheeps
+  package: todo-list
+  group: core
+  description: A sheep.
+`), true)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the plural should be the given one", func() {
+			So(spec.Model().EntityNamePlural, ShouldEqual, "Sheeps")
+		})
+
+		Convey("When I write it", func() {
+
+			buf := bytes.NewBuffer(nil)
+			_ = spec.Write(buf)
+
+			Convey("Then the plural should be written", func() {
+				So(buf.String(), ShouldContainSubstring, "  entity_name: Sheep\n  entity_name_plural: Sheeps\n")
+			})
+		})
+	})
+}
+
+func TestSpecification_PreserveOrder(t *testing.T) {
+
+	names := func(attrs []*Attribute) []string {
+		out := make([]string, len(attrs))
+		for i, a := range attrs {
+			out[i] = a.Name
+		}
+		return out
+	}
+
+	data := `# Model
+model:
+  rest_name: person
+  resource_name: people
+  entity_name: Person
+  package: todo-list
+  group: core
+  description: A person.
+  preserve_order: true
+
+# Attributes
+attributes:
+  v1:
+  - name: zeta
+    description: The zeta.
+    type: string
+
+  - include: person.attributes.yaml
+
+  - name: alpha
+    description: The alpha.
+    type: string
+
+  v2:
+  - name: beta
+    description: The beta.
+    type: string
+
+  - name: zeta
+    description: The new zeta.
+    type: string
+
+# Relations
+relations:
+- rest_name: zrel
+  get:
+    description: Retrieves the zrels.
+    parameters:
+      entries:
+      - name: z
+        description: The z.
+        type: string
+        example_value: z
+
+      - name: a
+        description: The a.
+        type: string
+        example_value: a
+
+- rest_name: arel
+  get:
+    description: Retrieves the arels.
+`
+
+	Convey("Given I read a specification preserving the order", t, func() {
+
+		spec := NewSpecification()
+		err := spec.Read(strings.NewReader(data), true)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the attributes should be in the declared order", func() {
+			So(names(spec.Attributes("v1")), ShouldResemble, []string{"zeta", "alpha"})
+			So(names(spec.Attributes("v2")), ShouldResemble, []string{"zeta", "alpha", "beta"})
+			So(spec.Attributes("v2")[0].Description, ShouldEqual, "The new zeta.")
+		})
+
+		Convey("When I write it", func() {
+
+			buf := bytes.NewBuffer(nil)
+			So(spec.Write(buf), ShouldBeNil)
+
+			Convey("Then the declared order should be kept", func() {
+				So(buf.String(), ShouldEqual, data)
+			})
+		})
+	})
+
+	Convey("Given I load a specification preserving the order with includes and abstracts", t, func() {
+
+		dir := t.TempDir()
+		So(os.WriteFile(filepath.Join(dir, "person.spec"), []byte(data), 0600), ShouldBeNil)
+		So(os.WriteFile(filepath.Join(dir, "person.attributes.yaml"), []byte("- name: middle\n  description: The middle.\n  type: string\n"), 0600), ShouldBeNil)
+
+		spec, err := LoadSpecification(filepath.Join(dir, "person.spec"), true)
+		So(err, ShouldBeNil)
+
+		base := NewSpecification()
+		So(base.Read(strings.NewReader("attributes:\n  v1:\n  - name: id\n    description: The id.\n    type: string\n"), true), ShouldBeNil)
+		So(spec.ApplyBaseSpecifications(base), ShouldBeNil)
+
+		Convey("Then the included and inherited attributes should be placed predictably", func() {
+			So(names(spec.Attributes("v1")), ShouldResemble, []string{"zeta", "middle", "alpha", "id"})
+		})
+	})
+
+	Convey("Given I read a specification without preserving the order", t, func() {
+
+		spec := NewSpecification()
+		err := spec.Read(strings.NewReader(strings.Replace(data, "  preserve_order: true\n", "", 1)), true)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the attributes should be sorted", func() {
+			So(names(spec.Attributes("v2")), ShouldResemble, []string{"alpha", "beta", "zeta"})
+		})
+
+		Convey("When I write it", func() {
+
+			buf := bytes.NewBuffer(nil)
+			So(spec.Write(buf), ShouldBeNil)
+
+			Convey("Then everything should be sorted", func() {
+				So(buf.String(), ShouldContainSubstring, "  v1:\n  - include: person.attributes.yaml\n\n  - name: alpha\n")
+				So(buf.String(), ShouldContainSubstring, "relations:\n- rest_name: arel\n")
+				So(buf.String(), ShouldContainSubstring, "      entries:\n      - name: a\n")
+			})
+		})
+	})
+
+	Convey("Given I read an abstract with the preserve order option", t, func() {
+
+		abs := "# Attributes\nattributes:\n  v1:\n  - name: zeta\n    description: The zeta.\n    type: string\n\n  - name: alpha\n    description: The alpha.\n    type: string\n"
+
+		spec := NewSpecification(SpecificationOptionPreserveOrder())
+		err := spec.Read(strings.NewReader(abs), true)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("When I write it", func() {
+
+			buf := bytes.NewBuffer(nil)
+			So(spec.Write(buf), ShouldBeNil)
+
+			Convey("Then the declared order should be kept", func() {
+				So(buf.String(), ShouldEqual, abs)
+			})
+		})
+	})
+}
+
+func TestSpecification_ZeroBounds(t *testing.T) {
+
+	Convey("Given I read a specification with zero bounds", t, func() {
+
+		spec := NewSpecification()
+		err := spec.Read(strings.NewReader(`model:
+  rest_name: thing
+  resource_name: things
+  entity_name: Thing
+  package: todo-list
+  group: core
+  description: A thing.
+attributes:
+  v1:
+  - name: name
+    description: The name.
+    type: string
+    max_length: 0
+  - name: count
+    description: The count.
+    type: integer
+    min_value: 0
+    exclusive_min_value: true
+    multiple_of: 2
+  - name: other
+    description: The other.
+    type: integer
+`), true)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Then the bounds should be set", func() {
+			So(*spec.Attribute("name", "v1").MaxLength, ShouldEqual, 0)
+			So(*spec.Attribute("count", "v1").MinValue, ShouldEqual, 0)
+			So(*spec.Attribute("count", "v1").MultipleOf, ShouldEqual, 2)
+			So(spec.Attribute("other", "v1").MinValue, ShouldBeNil)
+			So(spec.Attribute("other", "v1").MaxLength, ShouldBeNil)
+		})
+
+		Convey("When I write it", func() {
+
+			buf := bytes.NewBuffer(nil)
+			So(spec.Write(buf), ShouldBeNil)
+
+			Convey("Then the zero bounds should be written", func() {
+				So(buf.String(), ShouldContainSubstring, "    type: string\n    max_length: 0\n")
+				So(buf.String(), ShouldContainSubstring, "    min_value: 0\n    exclusive_min_value: true\n    multiple_of: 2\n")
+				So(buf.String(), ShouldContainSubstring, "  - name: other\n    description: The other.\n    type: integer\n")
+			})
+		})
+	})
+}
@@ -141,10 +141,13 @@ func (t TypeMapping) Mapping(mode string, externalType string) (mapping *TypeMap
 }
 
 // All returns the all the TypeMap for the given mode.
+// Types that are not mapped for the mode are skipped.
 func (t TypeMapping) All(mode string) (mapping []*TypeMap) {
 
 	for _, v := range t {
-		mapping = append(mapping, v[mode])
+		if m := v[mode]; m != nil {
+			mapping = append(mapping, m)
+		}
 	}
 
 	sort.Slice(mapping, func(i int, j int) bool {
@@ -0,0 +1,129 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+// Unused holds the sorted names of the definitions of a
+// SpecificationSet that are not referenced by any specification.
+type Unused struct {
+	Abstracts   []string
+	Types       []string
+	Validations []string
+	Parameters  []string
+	Enums       []string
+}
+
+// Empty returns true if there is no unused definition.
+func (u *Unused) Empty() bool {
+	return len(u.Abstracts) == 0 &&
+		len(u.Types) == 0 &&
+		len(u.Validations) == 0 &&
+		len(u.Parameters) == 0 &&
+		len(u.Enums) == 0
+}
+
+// FindUnused walks all the specifications of the given set and returns
+// the abstracts, type mapping keys, validation functions, global
+// parameter groups and named enums that are never referenced.
+// Abstracts, global parameters and named enums are only reported
+// if the set is a DefinitionProvider.
+func FindUnused(set SpecificationSet) *Unused {
+
+	abstracts := map[string]struct{}{}
+	types := map[string]struct{}{}
+	validations := map[string]struct{}{}
+	parameters := map[string]struct{}{}
+	enums := map[string]struct{}{}
+
+	useValidations := func(names []string) {
+		for _, v := range names {
+			if name, _, err := ParseValidationCall(v); err == nil {
+				validations[name] = struct{}{}
+			}
+		}
+	}
+
+	useParameters := func(actions ...*RelationAction) {
+		for _, a := range actions {
+			if a == nil {
+				continue
+			}
+			for _, key := range a.ParameterReferences {
+				parameters[key] = struct{}{}
+			}
+			if a.ParameterDefinition == nil {
+				continue
+			}
+			for _, p := range a.ParameterDefinition.Entries {
+				if p.EnumReference != "" {
+					enums[p.EnumReference] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for _, s := range set.Specifications() {
+
+		model := s.Model()
+
+		for _, ext := range model.Extends {
+			abstracts[ext] = struct{}{}
+		}
+
+		useValidations(model.Validations)
+		useParameters(model.Get, model.Update, model.Delete)
+
+		for _, version := range s.AttributeVersions() {
+			for _, attr := range withProperties(s.Attributes(version)) {
+
+				if attr.Type == AttributeTypeExt {
+					types[attr.SubType] = struct{}{}
+				}
+
+				if attr.EnumReference != "" {
+					enums[attr.EnumReference] = struct{}{}
+				}
+
+				useValidations(attr.Validations)
+			}
+		}
+
+		for _, rel := range s.Relations() {
+			useParameters(rel.Create, rel.Get, rel.Update, rel.Delete)
+		}
+	}
+
+	u := &Unused{
+		Types:       unreferenced(set.TypeMapping(), types),
+		Validations: unreferenced(set.ValidationMapping(), validations),
+	}
+
+	if p, ok := set.(DefinitionProvider); ok {
+		u.Abstracts = unreferenced(p.Abstracts(), abstracts)
+		u.Parameters = unreferenced(p.ParameterMapping(), parameters)
+		u.Enums = unreferenced(p.EnumMapping(), enums)
+	}
+
+	return u
+}
+
+// unreferenced returns the sorted keys of defined that are not in used.
+func unreferenced[T any](defined map[string]T, used map[string]struct{}) []string {
+
+	var out []string
+	for _, name := range sortedKeys(defined) {
+		if _, ok := used[name]; !ok {
+			out = append(out, name)
+		}
+	}
+
+	return out
+}
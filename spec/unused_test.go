@@ -0,0 +1,128 @@
+// Copyright 2019 Aporeto Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnused_FindUnused(t *testing.T) {
+
+	Convey("Given I have the test specification set", t, func() {
+
+		set, err := LoadSpecificationSet("./tests", nil, nil, "")
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("When I call FindUnused", func() {
+
+			unused := FindUnused(set)
+
+			Convey("Then only the type mappings should be unused", func() {
+				So(unused.Empty(), ShouldBeFalse)
+				So(unused.Abstracts, ShouldBeEmpty)
+				So(unused.Types, ShouldResemble, []string{"int_array", "string_map", "toto"})
+				So(unused.Validations, ShouldBeEmpty)
+				So(unused.Parameters, ShouldBeEmpty)
+				So(unused.Enums, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given I have a specification set with unused definitions", t, func() {
+
+		list, _ := os.ReadFile("./tests/list.spec")
+		task, _ := os.ReadFile("./tests/task.spec")
+		user, _ := os.ReadFile("./tests/user.spec")
+
+		dir := copyTestFolder(t, map[string]string{
+			"@other.abs": "attributes:\n  v1:\n  - name: other\n    description: Other.\n    type: string\n",
+			"list.spec":  strings.Replace(string(list), "    - sharedParameterB\n", "", 1),
+			"task.spec": strings.NewReplacer(
+				"    - $nospace\n", "    - $range(1, 2)\n",
+				"    allowed_choices:\n    - DONE\n    - PROGRESS\n    - TODO\n", "    enum_reference: status\n",
+			).Replace(string(task)),
+			"_enums.mapping": "priority:\n  values:\n  - value: High\n\nstatus:\n  values:\n  - value: TODO\n",
+			"user.spec":      strings.Replace(string(user), "  - $username\n", "  - $nocap\n", 1),
+			"_validation.mapping": `$nocap:
+  test:
+    name: noCap
+
+$nospace:
+  test:
+    name: noSpace
+
+$range:
+  test:
+    name: validate.Range
+
+$username:
+  test:
+    name: validate.CheckUserName
+`,
+		})
+
+		set, err := LoadSpecificationSet(dir, nil, nil, "")
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("When I call FindUnused", func() {
+
+			unused := FindUnused(set)
+
+			Convey("Then the unused definitions should be correct", func() {
+				So(unused.Abstracts, ShouldResemble, []string{"@other"})
+				So(unused.Validations, ShouldResemble, []string{"$nospace", "$username"})
+				So(unused.Parameters, ShouldResemble, []string{"sharedParameterB"})
+				So(unused.Enums, ShouldResemble, []string{"priority"})
+			})
+		})
+	})
+
+	Convey("Given I have a specification set with a named enum used by a nested property", t, func() {
+
+		task, _ := os.ReadFile("./tests/task.spec")
+
+		dir := copyTestFolder(t, map[string]string{
+			"task.spec": strings.Replace(
+				string(task),
+				"  - name: description\n",
+				"  - name: details\n    description: The details.\n    type: object\n    properties:\n    - name: priority\n      description: The priority.\n      type: enum\n      enum_reference: priority\n\n  - name: description\n",
+				1,
+			),
+			"_enums.mapping": "priority:\n  values:\n  - value: High\n",
+		})
+
+		set, err := LoadSpecificationSet(dir, nil, nil, "")
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("When I call FindUnused", func() {
+
+			unused := FindUnused(set)
+
+			Convey("Then the enum should be used", func() {
+				So(unused.Enums, ShouldBeEmpty)
+			})
+		})
+	})
+}
@@ -17,15 +17,43 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/xeipuuv/gojsonschema"
 	yaml "gopkg.in/yaml.v2"
 )
 
+// ValidationParameterType represents the type of a ValidationParameter.
+type ValidationParameterType string
+
+// Various values for ValidationParameterType.
+const (
+	ValidationParameterTypeString  ValidationParameterType = "string"
+	ValidationParameterTypeInteger ValidationParameterType = "integer"
+	ValidationParameterTypeFloat   ValidationParameterType = "float"
+	ValidationParameterTypeBoolean ValidationParameterType = "boolean"
+)
+
+// A ValidationParameter declares an argument accepted by a validation function.
+// If Variadic is true, the parameter must be the last one and accepts any number
+// of arguments.
+type ValidationParameter struct {
+	Name     string                  `yaml:"name,omitempty"           json:"name,omitempty"`
+	Type     ValidationParameterType `yaml:"type,omitempty"           json:"type,omitempty"`
+	Variadic bool                    `yaml:"variadic,omitempty"       json:"variadic,omitempty"`
+}
+
 // A ValidationMap represent a single ValidationMap.
 type ValidationMap struct {
-	Name   string `yaml:"name,omitempty"           json:"name,omitempty"`
-	Import string `yaml:"import,omitempty"         json:"import,omitempty"`
+	Name       string                 `yaml:"name,omitempty"           json:"name,omitempty"`
+	Import     string                 `yaml:"import,omitempty"         json:"import,omitempty"`
+	Parameters []*ValidationParameter `yaml:"parameters,omitempty"     json:"parameters,omitempty"`
+
+	// Arguments holds the values given to the validation function
+	// in the specification, converted according to the Parameters.
+	// Values are string, int64, float64 or bool.
+	Arguments []any `yaml:"-" json:"-"`
 }
 
 // ValidationMapping holds the mapping of the validation function.
@@ -122,14 +150,43 @@ func (v ValidationMapping) Write(writer io.Writer) error {
 }
 
 // Mapping returns the ValidationMap for the given external type.
+// The function name can contain arguments, like $range(1, 10). In that
+// case, the arguments are checked against the declared parameters and
+// the returned ValidationMap is a copy holding them in Arguments.
+// With an empty mode, it only checks that the function is declared.
 func (v ValidationMapping) Mapping(mode string, functionName string) (mapping *ValidationMap, err error) {
 
-	m, ok := v[functionName]
+	name, args, err := ParseValidationCall(functionName)
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := v[name]
 	if !ok {
-		return nil, fmt.Errorf("no function '%s' found in type mapping mode %s", functionName, mode)
+		if mode == "" {
+			return nil, fmt.Errorf("no function '%s' found", name)
+		}
+		return nil, fmt.Errorf("no function '%s' found in type mapping mode %s", name, mode)
 	}
 
-	return m[mode], nil
+	mapping = m[mode]
+	if mapping == nil {
+		return nil, nil
+	}
+
+	arguments, err := mapping.checkArguments(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments for function '%s' in mode %s: %s", name, mode, err)
+	}
+
+	if arguments == nil {
+		return mapping, nil
+	}
+
+	out := *mapping
+	out.Arguments = arguments
+
+	return &out, nil
 }
 
 // Validate validates the type mappings against the schema.
@@ -152,5 +209,184 @@ func (v ValidationMapping) Validate() []error {
 		return makeSchemaValidationError("_validation.mapping", res.Errors())
 	}
 
-	return nil
+	var errs []error
+
+	for _, fname := range sortedKeys(v) {
+		for _, mode := range sortedKeys(v[fname]) {
+
+			m := v[fname][mode]
+			if m == nil {
+				continue
+			}
+
+			for i, p := range m.Parameters {
+				if p.Variadic && i != len(m.Parameters)-1 {
+					errs = append(errs, fmt.Errorf("_validation.mapping: %s.%s: variadic parameter '%s' must be the last one", fname, mode, p.Name))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// providerKey returns the key of the ValidationMap in the validation
+// providers of models and attributes: its name followed by its
+// arguments, if any, so calls with different arguments are all kept.
+func (m *ValidationMap) providerKey() string {
+
+	if len(m.Arguments) == 0 {
+		return m.Name
+	}
+
+	args := make([]string, len(m.Arguments))
+	for i, a := range m.Arguments {
+		if s, ok := a.(string); ok {
+			args[i] = strconv.Quote(s)
+		} else {
+			args[i] = fmt.Sprintf("%v", a)
+		}
+	}
+
+	return m.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// checkArguments checks the given raw arguments against the
+// parameters of the ValidationMap and returns their converted values.
+func (m *ValidationMap) checkArguments(args []string) ([]any, error) {
+
+	required := len(m.Parameters)
+	variadic := required > 0 && m.Parameters[required-1].Variadic
+	if variadic {
+		required--
+	}
+
+	switch {
+	case variadic && len(args) < required:
+		return nil, fmt.Errorf("expected at least %d argument(s), got %d", required, len(args))
+	case !variadic && len(args) != required:
+		return nil, fmt.Errorf("expected %d argument(s), got %d", required, len(args))
+	}
+
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	out := make([]any, len(args))
+
+	for i, arg := range args {
+
+		p := m.Parameters[len(m.Parameters)-1]
+		if i < len(m.Parameters) {
+			p = m.Parameters[i]
+		}
+
+		v, err := convertValidationArgument(arg, p.Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %s", i+1, p.Name, err)
+		}
+
+		out[i] = v
+	}
+
+	return out, nil
+}
+
+// convertValidationArgument converts the given raw argument to the given type.
+func convertValidationArgument(arg string, typ ValidationParameterType) (any, error) {
+
+	switch typ {
+
+	case ValidationParameterTypeString:
+		if len(arg) < 2 || arg[0] != '"' {
+			return nil, fmt.Errorf("'%s' is not a string", arg)
+		}
+		return strconv.Unquote(arg)
+
+	case ValidationParameterTypeInteger:
+		i, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not an integer", arg)
+		}
+		return i, nil
+
+	case ValidationParameterTypeFloat:
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a float", arg)
+		}
+		return f, nil
+
+	case ValidationParameterTypeBoolean:
+		switch arg {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		default:
+			return nil, fmt.Errorf("'%s' is not a boolean", arg)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported parameter type '%s'", typ)
+	}
+}
+
+// ParseValidationCall parses a validation reference like $name or
+// $name(arg1, arg2) and returns the function name and the raw arguments.
+// String arguments are returned quoted.
+func ParseValidationCall(call string) (name string, args []string, err error) {
+
+	call = strings.TrimSpace(call)
+
+	idx := strings.IndexByte(call, '(')
+	if idx == -1 {
+		return call, nil, nil
+	}
+
+	if call[len(call)-1] != ')' {
+		return "", nil, fmt.Errorf("invalid validation call '%s': missing closing parenthesis", call)
+	}
+
+	name = strings.TrimSpace(call[:idx])
+	inner := strings.TrimSpace(call[idx+1 : len(call)-1])
+
+	if inner == "" {
+		return name, nil, nil
+	}
+
+	var current strings.Builder
+	var inString, escaped bool
+
+	for _, c := range inner {
+
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case c == ',' && !inString:
+			args = append(args, strings.TrimSpace(current.String()))
+			current.Reset()
+			continue
+		}
+
+		_, _ = current.WriteRune(c)
+	}
+
+	if inString {
+		return "", nil, fmt.Errorf("invalid validation call '%s': unterminated string", call)
+	}
+
+	args = append(args, strings.TrimSpace(current.String()))
+
+	for _, arg := range args {
+		if arg == "" {
+			return "", nil, fmt.Errorf("invalid validation call '%s': empty argument", call)
+		}
+	}
+
+	return name, args, nil
 }
@@ -12,6 +12,7 @@
 package spec
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -92,3 +93,160 @@ func TestValidationMapping_Mapping(t *testing.T) {
 		})
 	})
 }
+
+const parameterizedValidationMapping = `$range:
+  test:
+    name: validate.Range
+    parameters:
+    - name: min
+      type: integer
+    - name: max
+      type: float
+
+$oneOfPrefix:
+  test:
+    name: validate.OneOfPrefix
+    parameters:
+    - name: strict
+      type: boolean
+    - name: prefixes
+      type: string
+      variadic: true
+`
+
+func TestValidationMapping_ParseValidationCall(t *testing.T) {
+
+	Convey("Given I have various validation calls", t, func() {
+
+		tests := []struct {
+			call string
+			name string
+			args []string
+			err  string
+		}{
+			{call: "$username", name: "$username"},
+			{call: "$range()", name: "$range"},
+			{call: "$range(1, 65535)", name: "$range", args: []string{"1", "65535"}},
+			{call: `$oneOfPrefix("http", "a, \"b\"")`, name: "$oneOfPrefix", args: []string{`"http"`, `"a, \"b\""`}},
+			{call: "$range(1, 2", err: "invalid validation call '$range(1, 2': missing closing parenthesis"},
+			{call: `$oneOfPrefix("http)`, err: `invalid validation call '$oneOfPrefix("http)': unterminated string`},
+			{call: "$range(1,, 2)", err: "invalid validation call '$range(1,, 2)': empty argument"},
+		}
+
+		for _, tt := range tests {
+
+			Convey("When I parse "+tt.call, func() {
+
+				name, args, err := ParseValidationCall(tt.call)
+
+				Convey("Then the result should be correct", func() {
+					if tt.err != "" {
+						So(err, ShouldNotBeNil)
+						So(err.Error(), ShouldEqual, tt.err)
+					} else {
+						So(err, ShouldBeNil)
+						So(name, ShouldEqual, tt.name)
+						So(args, ShouldResemble, tt.args)
+					}
+				})
+			})
+		}
+	})
+}
+
+func TestValidationMapping_MappingWithArguments(t *testing.T) {
+
+	Convey("Given I have a mapping with parameterized functions", t, func() {
+
+		vm := NewValidationMapping()
+		err := vm.Read(strings.NewReader(parameterizedValidationMapping), true)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("When I call Mapping with valid arguments", func() {
+
+			m, err := vm.Mapping("test", "$range(1, 65535)")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the arguments should be converted", func() {
+				So(m.Name, ShouldEqual, "validate.Range")
+				So(m.Arguments, ShouldResemble, []any{int64(1), float64(65535)})
+			})
+
+			Convey("Then the mapping itself should not be modified", func() {
+				So(vm["$range"]["test"].Arguments, ShouldBeNil)
+			})
+		})
+
+		Convey("When I call Mapping with variadic arguments", func() {
+
+			m, err := vm.Mapping("test", `$oneOfPrefix(true, "http", "https")`)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the arguments should be converted", func() {
+				So(m.Arguments, ShouldResemble, []any{true, "http", "https"})
+			})
+		})
+
+		Convey("When I call Mapping with an unknown mode", func() {
+
+			m, err := vm.Mapping("other", "$range(1, 65535)")
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+				So(m, ShouldBeNil)
+			})
+		})
+
+		Convey("When I call Mapping with invalid arguments", func() {
+
+			tests := map[string]string{
+				"$range":                 "invalid arguments for function '$range' in mode test: expected 2 argument(s), got 0",
+				"$range(1)":              "invalid arguments for function '$range' in mode test: expected 2 argument(s), got 1",
+				`$range("1", 2)`:         `invalid arguments for function '$range' in mode test: argument 1 (min): '"1"' is not an integer`,
+				"$range(1, high)":        "invalid arguments for function '$range' in mode test: argument 2 (max): 'high' is not a float",
+				"$oneOfPrefix()":         "invalid arguments for function '$oneOfPrefix' in mode test: expected at least 1 argument(s), got 0",
+				`$oneOfPrefix(true, 42)`: "invalid arguments for function '$oneOfPrefix' in mode test: argument 2 (prefixes): '42' is not a string",
+				`$oneOfPrefix(yes)`:      "invalid arguments for function '$oneOfPrefix' in mode test: argument 1 (strict): 'yes' is not a boolean",
+			}
+
+			for call, expected := range tests {
+
+				_, err := vm.Mapping("test", call)
+
+				Convey("Then the error for "+call+" should be correct", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, expected)
+				})
+			}
+		})
+	})
+
+	Convey("Given I have a mapping with a variadic parameter that is not the last one", t, func() {
+
+		vm := NewValidationMapping()
+		err := vm.Read(strings.NewReader(`$bad:
+  test:
+    name: validate.Bad
+    parameters:
+    - name: values
+      type: string
+      variadic: true
+    - name: other
+      type: string
+`), true)
+
+		Convey("Then err should not be nil", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "_validation.mapping: $bad.test: variadic parameter 'values' must be the last one")
+		})
+	})
+}
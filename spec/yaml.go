@@ -56,6 +56,29 @@ func toYAMLMapSlice(s any) yaml.MapSlice {
 	return out
 }
 
+// attributeToYAMLMapSlice converts the given attribute, as declared
+// in the specification, and its nested properties to a yaml.MapSlice.
+func attributeToYAMLMapSlice(attr *Attribute) yaml.MapSlice {
+
+	out := toYAMLMapSlice(attr.declared())
+
+	for i, item := range out {
+
+		if item.Key != "properties" {
+			continue
+		}
+
+		props := make([]yaml.MapSlice, len(attr.Properties))
+		for j, p := range attr.Properties {
+			props[j] = attributeToYAMLMapSlice(p)
+		}
+
+		out[i].Value = props
+	}
+
+	return out
+}
+
 func splitTags(tag string) (string, bool) {
 
 	if tag == "" || tag == "-" {